@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Поздравления, временно снятые с показа (birth_day -> true). Задаётся через
+// BLOCKED_IDS при запуске; данные поздравлений при этом не удаляются.
+var blockedIDs = map[int]bool{}
+
+// Разбирает BLOCKED_IDS: birth_day через запятую, например "3,17"
+func parseBlockedIDs(value string) (map[int]bool, error) {
+	blocked := make(map[int]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		birth_day, err := strconv.Atoi(part)
+		if err != nil || birth_day < 1 || birth_day > len(greetings) {
+			return nil, fmt.Errorf("BLOCKED_IDS: ожидается birth_day от 1 до %d, получено %q", len(greetings), part)
+		}
+		blocked[birth_day] = true
+	}
+	return blocked, nil
+}
+
+func isBlocked(birth_day int) bool {
+	return blockedIDs[birth_day]
+}
@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestParseBlockedIDs(t *testing.T) {
+	blocked, err := parseBlockedIDs(" 3, 17,,3 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocked) != 2 || !blocked[3] || !blocked[17] {
+		t.Errorf("получено %v, ожидалось {3, 17}", blocked)
+	}
+	for _, value := range []string{"0", "32", "abc", "3,-1"} {
+		if _, err := parseBlockedIDs(value); err == nil {
+			t.Errorf("parseBlockedIDs(%q): ожидалась ошибка", value)
+		}
+	}
+}
+
+func TestBlockedGreeting(t *testing.T) {
+	defer func(saved map[int]bool) { blockedIDs = saved }(blockedIDs)
+	blockedIDs = map[int]bool{3: true}
+
+	if _, err := greetingFromArgs(map[string]interface{}{"birth_day": 3}); errorCode(err) != codeBlocked {
+		t.Errorf("birth_day 3: ошибка %v, ожидался %s", err, codeBlocked)
+	}
+	for _, greeting := range allGreetings() {
+		if greeting.BirthDay == 3 {
+			t.Error("заблокированное поздравление попало в пул выбора")
+		}
+	}
+	result := execQuery(t, `{ greetingsContaining(emoji: "🌷") { birth_day } }`, nil)
+	for _, g := range result.Data.(map[string]interface{})["greetingsContaining"].([]interface{}) {
+		if g.(map[string]interface{})["birth_day"] == 3 {
+			t.Error("заблокированное поздравление попало в greetingsContaining")
+		}
+	}
+}
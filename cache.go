@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/handler"
+)
+
+// Кэш сериализованных GraphQL-ответов с TTL и ограничением по числу записей.
+// Ключ — нормализованный запрос, переменные, имя операции и Accept-Language.
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[string]*list.Element
+	order   *list.List // от новых к старым, для вытеснения
+}
+
+// Попадания и промахи кэша ответов для /status
+var cacheHits, cacheMisses atomic.Int64
+
+type cachedResponse struct {
+	key     string
+	body    []byte
+	events  []contentEvent // повторяются в контентном логе при каждом HIT
+	expires time.Time
+}
+
+func newResponseCache(ttl time.Duration, maxSize int) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *responseCache) get(key string) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cachedResponse)
+	if clock.Now().After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry, true
+}
+
+func (c *responseCache) put(key string, body []byte, events []contentEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+	}
+	c.entries[key] = c.order.PushFront(&cachedResponse{key: key, body: body, events: events, expires: clock.Now().Add(c.ttl)})
+	for c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+// Сбрасывает кэш целиком (после мутаций)
+func (c *responseCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Поля, ответ которых меняется между одинаковыми запросами: случайный выбор,
+// курсор round-robin, смена дня или периода. Запросы с ними не кэшируются.
+// randomGreetings с seed детерминирован и исключением не считается.
+var uncacheableFields = map[string]bool{
+	"randomGreeting":      true,
+	"randomGreetings":     true,
+	"greetingOfThePeriod": true,
+}
+
+// Выбирает ли документ хотя бы одно поле из uncacheableFields (в том числе через фрагменты)
+func selectsUncacheable(doc *ast.Document, variables map[string]interface{}) bool {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+	visited := make(map[string]bool)
+	var walk func(set *ast.SelectionSet) bool
+	walk = func(set *ast.SelectionSet) bool {
+		if set == nil {
+			return false
+		}
+		for _, sel := range set.Selections {
+			switch sel := sel.(type) {
+			case *ast.Field:
+				if uncacheableFields[sel.Name.Value] && !(sel.Name.Value == "randomGreetings" && hasSeed(sel, variables)) {
+					return true
+				}
+				if walk(sel.SelectionSet) {
+					return true
+				}
+			case *ast.InlineFragment:
+				if walk(sel.SelectionSet) {
+					return true
+				}
+			case *ast.FragmentSpread:
+				name := sel.Name.Value
+				if fragment, ok := fragments[name]; ok && !visited[name] {
+					visited[name] = true
+					if walk(fragment.SelectionSet) {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok && walk(op.SelectionSet) {
+			return true
+		}
+	}
+	return false
+}
+
+// Передан ли полю аргумент seed со значением, отличным от null
+func hasSeed(field *ast.Field, variables map[string]interface{}) bool {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "seed" {
+			continue
+		}
+		switch value := arg.Value.(type) {
+		case *ast.Variable:
+			return variables[value.Name.Value] != nil
+		case *ast.IntValue:
+			return true
+		}
+	}
+	return false
+}
+
+// Строит ключ кэша; cacheable=false для мутаций, запросов, которые не удалось
+// разобрать, и запросов с полями из uncacheableFields
+func responseCacheKey(r *http.Request, opts *handler.RequestOptions) (key string, cacheable, mutation bool) {
+	doc, err := parser.Parse(parser.ParseParams{Source: opts.Query})
+	if err != nil {
+		return "", false, false
+	}
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok && op.Operation != ast.OperationTypeQuery {
+			return "", false, op.Operation == ast.OperationTypeMutation
+		}
+	}
+	if selectsUncacheable(doc, opts.Variables) {
+		return "", false, false
+	}
+	variables, err := json.Marshal(opts.Variables)
+	if err != nil {
+		return "", false, false
+	}
+	normalized, _ := printer.Print(doc).(string)
+	return normalized + "\x00" + string(variables) + "\x00" + opts.OperationName + "\x00" + r.Header.Get("Accept-Language"), true, false
+}
+
+// Перехватывает ответ обработчика, чтобы сохранить его в кэш
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Отдаёт повторяющиеся запросы из кэша; заголовок X-Cache показывает HIT или MISS
+func (c *responseCache) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isBrowserRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Тело читается дважды: для ключа и для самого обработчика
+		var raw []byte
+		if r.Body != nil {
+			var err error
+			raw, err = io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "не удалось прочитать тело запроса", http.StatusBadRequest)
+				return
+			}
+		}
+		r.Body = io.NopCloser(bytes.NewReader(raw))
+		opts := handler.NewRequestOptions(r)
+		r.Body = io.NopCloser(bytes.NewReader(raw))
+
+		key, cacheable, mutation := responseCacheKey(r, opts)
+		if mutation {
+			defer c.purge()
+		}
+		if !cacheable {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if entry, ok := c.get(key); ok {
+			cacheHits.Add(1)
+			logCachedGreetings(entry.events)
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Header().Set("X-Cache", "HIT")
+			w.Write(entry.body)
+			return
+		}
+
+		cacheMisses.Add(1)
+		w.Header().Set("X-Cache", "MISS")
+		ctx, events := withContentEventRecorder(r.Context())
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+		if rec.status == http.StatusOK {
+			c.put(key, rec.body.Bytes(), events.recorded())
+		}
+	})
+}
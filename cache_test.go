@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/handler"
+)
+
+func TestResponseCacheKeySkipsNondeterministicFields(t *testing.T) {
+	tests := []struct {
+		query     string
+		variables map[string]interface{}
+		cacheable bool
+	}{
+		{`{ greeting(birth_day: 2) { text } }`, nil, true},
+		{`{ randomGreeting { text } }`, nil, false},
+		{`{ r: randomGreeting { text } }`, nil, false},
+		{`query { ...F } fragment F on Query { randomGreeting { text } }`, nil, false},
+		{`{ ... on Query { greetingOfThePeriod(period: WEEK) { text } } }`, nil, false},
+		{`{ randomGreetings(count: 2) { text } }`, nil, false},
+		{`{ randomGreetings(count: 2, seed: 7) { text } }`, nil, true},
+		{`query($seed: Int) { randomGreetings(count: 2, seed: $seed) { text } }`, map[string]interface{}{"seed": 7}, true},
+		{`query($seed: Int) { randomGreetings(count: 2, seed: $seed) { text } }`, nil, false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/graphql", nil)
+		_, cacheable, _ := responseCacheKey(r, &handler.RequestOptions{Query: tt.query, Variables: tt.variables})
+		if cacheable != tt.cacheable {
+			t.Errorf("%s: cacheable = %v, ожидалось %v", tt.query, cacheable, tt.cacheable)
+		}
+	}
+}
+
+// GraphQL-обработчик-заглушка, считающий вызовы
+type countingHandler struct{ calls int }
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"data":{"call":%d}}`, h.calls)
+}
+
+func postQuery(h http.Handler, query string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]string{"query": query})
+	r := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestResponseCacheMissThenHit(t *testing.T) {
+	next := &countingHandler{}
+	h := newResponseCache(time.Minute, 10).middleware(next)
+	hits, misses := cacheHits.Load(), cacheMisses.Load()
+
+	first := postQuery(h, `{ greeting(birth_day: 1) { text } }`)
+	second := postQuery(h, `{ greeting(birth_day: 1) { text } }`)
+	if first.Header().Get("X-Cache") != "MISS" || second.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("X-Cache: %q, затем %q; ожидалось MISS, затем HIT", first.Header().Get("X-Cache"), second.Header().Get("X-Cache"))
+	}
+	if next.calls != 1 || second.Body.String() != first.Body.String() {
+		t.Errorf("обработчик вызван %d раз, ответы %q и %q", next.calls, first.Body.String(), second.Body.String())
+	}
+	if cacheHits.Load()-hits != 1 || cacheMisses.Load()-misses != 1 {
+		t.Errorf("счётчики: +%d попаданий, +%d промахов; ожидалось по одному", cacheHits.Load()-hits, cacheMisses.Load()-misses)
+	}
+}
+
+func TestResponseCacheEvictsOldest(t *testing.T) {
+	next := &countingHandler{}
+	h := newResponseCache(time.Minute, 2).middleware(next)
+
+	postQuery(h, `{ greeting(birth_day: 1) { text } }`)
+	postQuery(h, `{ greeting(birth_day: 2) { text } }`)
+	postQuery(h, `{ greeting(birth_day: 3) { text } }`)
+	if got := postQuery(h, `{ greeting(birth_day: 3) { text } }`).Header().Get("X-Cache"); got != "HIT" {
+		t.Errorf("последний запрос: X-Cache %q, ожидался HIT", got)
+	}
+	if got := postQuery(h, `{ greeting(birth_day: 1) { text } }`).Header().Get("X-Cache"); got != "MISS" {
+		t.Errorf("самый старый запрос: X-Cache %q, ожидался MISS после вытеснения", got)
+	}
+}
+
+func TestResponseCachePurgedByMutation(t *testing.T) {
+	next := &countingHandler{}
+	h := newResponseCache(time.Minute, 10).middleware(next)
+
+	postQuery(h, `{ greeting(birth_day: 1) { text } }`)
+	postQuery(h, `mutation { remixGreeting(birth_day1: 1, birth_day2: 2) { text } }`)
+	if got := postQuery(h, `{ greeting(birth_day: 1) { text } }`).Header().Get("X-Cache"); got != "MISS" {
+		t.Errorf("после мутации: X-Cache %q, ожидался MISS", got)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Запрос CLI к серверу: пользовательский ввод передаётся только через variables
+const greetingQuery = `query Greeting($birth_day: Int!) { greeting(birth_day: $birth_day) { text flowers } }`
+
+// Тело GraphQL-запроса в формате {"query": ..., "variables": {...}}
+type graphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Ответ сервера на greetingQuery
+type greetingResult struct {
+	Data struct {
+		Greeting struct {
+			Text    string `json:"text"`
+			Flowers string `json:"flowers"`
+		} `json:"greeting"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Интерактивный цикл: читает birth_day из in и печатает в out поздравления,
+// полученные от GraphQL-сервера по адресу endpoint. В режиме quiet, а также
+// когда out не терминал (вывод перенаправлен), приветствие и подсказки
+// не выводятся. Отмена ctx (сигнал остановки) прекращает приём ввода,
+// не дожидаясь следующей строки.
+func runCLI(ctx context.Context, in io.Reader, out io.Writer, endpoint string, quiet bool) {
+	quiet = quiet || !isTerminal(out)
+	if !quiet {
+		fmt.Fprintf(out, "Введите birth_day (от 1 до %d) для получения текста и цветов. Для выхода введите 'exit' или нажмите Ctrl+C.\n", len(greetings))
+	}
+	// Читаем ввод построчно в отдельной горутине, чтобы ожидание строки
+	// не мешало реагировать на остановку
+	scanner := bufio.NewScanner(in)
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		if err := scanner.Err(); err != nil {
+			log.Printf("Ошибка чтения ввода: %v", err)
+		}
+	}()
+
+	for {
+		if !quiet {
+			fmt.Fprint(out, "birth_day: ")
+		}
+		var line string
+		select {
+		case <-ctx.Done():
+			if !quiet {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintln(out, "Сервер останавливается, ввод больше не принимается.")
+			return
+		case l, ok := <-lines:
+			if !ok {
+				if !quiet {
+					fmt.Fprintln(out, "Завершение работы.")
+				}
+				return
+			}
+			line = l
+		}
+		// Строка могла прийти одновременно с сигналом остановки
+		if ctx.Err() != nil {
+			fmt.Fprintln(out, "Сервер останавливается, ввод больше не принимается.")
+			return
+		}
+		input := strings.TrimSpace(line)
+		if input == "" {
+			continue
+		}
+		if input == "exit" {
+			if !quiet {
+				fmt.Fprintln(out, "Завершение работы.")
+			}
+			return
+		}
+
+		// Диапазон проверяется до запроса для быстрой подсказки; окончательно его проверяет сервер
+		birth_day, err := strconv.Atoi(input)
+		if err != nil || birth_day < 1 || birth_day > len(greetings) {
+			fmt.Fprintf(out, "Пожалуйста, введите одно число от 1 до %d\n", len(greetings))
+			continue
+		}
+
+		result, err := fetchGreeting(ctx, endpoint, birth_day)
+		if err != nil {
+			if ctx.Err() != nil {
+				fmt.Fprintln(out, "Сервер останавливается, ввод больше не принимается.")
+				return
+			}
+			log.Print(err)
+			continue
+		}
+
+		if len(result.Errors) > 0 {
+			fmt.Fprintf(out, "Ошибка от сервера: %s\n", result.Errors[0].Message)
+		} else {
+			fmt.Fprintf(out, "Поздравление: %s\n", result.Data.Greeting.Text)
+			fmt.Fprintf(out, "Цветы: %s\n\n", result.Data.Greeting.Flowers)
+		}
+	}
+}
+
+// Является ли w терминалом
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Запрашивает поздравление у сервера; birth_day передаётся как переменная
+func fetchGreeting(ctx context.Context, endpoint string, birth_day int) (*greetingResult, error) {
+	payload, err := json.Marshal(graphqlRequest{
+		Query:     greetingQuery,
+		Variables: map[string]interface{}{"birth_day": birth_day},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка формирования запроса: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка формирования запроса: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при отправке запроса: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result greetingResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("ошибка декодирования ответа: %v", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/handler"
+)
+
+// Сервер GraphQL с настоящей схемой для проверок CLI
+func newTestGraphQLServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	schema, err := newSchema()
+	if err != nil {
+		t.Fatalf("newSchema: %v", err)
+	}
+	srv := httptest.NewServer(handler.New(&handler.Config{Schema: &schema}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchGreetingSendsVariables(t *testing.T) {
+	var got graphqlRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("тело запроса не JSON: %v", err)
+		}
+		w.Write([]byte(`{"data":{"greeting":{"text":"ok","flowers":""}}}`))
+	}))
+	defer srv.Close()
+
+	if _, err := fetchGreeting(context.Background(), srv.URL, 5); err != nil {
+		t.Fatal(err)
+	}
+	if got.Query != greetingQuery {
+		t.Errorf("query = %q, ожидался %q", got.Query, greetingQuery)
+	}
+	// Числа в JSON декодируются как float64
+	if got.Variables["birth_day"] != float64(5) {
+		t.Errorf("variables = %v, ожидалось birth_day: 5", got.Variables)
+	}
+}
+
+func TestFetchGreetingVariablesParsedByServer(t *testing.T) {
+	srv := newTestGraphQLServer(t)
+	result, err := fetchGreeting(context.Background(), srv.URL, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("ошибки сервера: %v", result.Errors)
+	}
+	if result.Data.Greeting.Text != greetings[4] || result.Data.Greeting.Flowers != flowers[4] {
+		t.Errorf("получено %+v, ожидалось поздравление 5", result.Data.Greeting)
+	}
+}
+
+func TestRunCLIMultiTokenLines(t *testing.T) {
+	srv := newTestGraphQLServer(t)
+	var out strings.Builder
+	runCLI(context.Background(), strings.NewReader("3 extra\n  5  \n7 8\nexit\n"), &out, srv.URL, false)
+
+	want := "Пожалуйста, введите одно число от 1 до 31\n" +
+		"Поздравление: " + greetings[4] + "\n" +
+		"Цветы: " + flowers[4] + "\n\n" +
+		"Пожалуйста, введите одно число от 1 до 31\n"
+	if out.String() != want {
+		t.Errorf("вывод:\n%s\nожидалось:\n%s", out.String(), want)
+	}
+}
+
+func TestRunCLIRejectsOutOfRangeBeforeRequest(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data":{"greeting":{"text":"","flowers":""}}}`))
+	}))
+	defer srv.Close()
+
+	var out strings.Builder
+	input := "0\n-5\n32\n2147483648\n99999999999999999999\n"
+	runCLI(context.Background(), strings.NewReader(input), &out, srv.URL, false)
+
+	if requests != 0 {
+		t.Errorf("к серверу отправлено запросов: %d, ожидалось 0", requests)
+	}
+	if n := strings.Count(out.String(), "Пожалуйста, введите одно число"); n != 5 {
+		t.Errorf("подсказок о диапазоне: %d, ожидалось 5; вывод:\n%s", n, out.String())
+	}
+}
+
+func TestRunCLINoPromptForNonTerminal(t *testing.T) {
+	srv := newTestGraphQLServer(t)
+	var out strings.Builder
+	runCLI(context.Background(), strings.NewReader("5\n"), &out, srv.URL, false)
+
+	for _, prompt := range []string{"Введите birth_day", "birth_day: ", "Завершение работы."} {
+		if strings.Contains(out.String(), prompt) {
+			t.Errorf("вывод содержит %q:\n%s", prompt, out.String())
+		}
+	}
+	if !strings.HasPrefix(out.String(), "Поздравление: "+greetings[4]) {
+		t.Errorf("вывод:\n%s\nожидалось поздравление 5 без приглашения", out.String())
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Источник текущего времени для всего, что зависит от даты: поздравление дня
+// и периода, TTL кэша, окна лимита /surprise, время в журнале содержимого
+type Clock interface {
+	Now() time.Time
+}
+
+// Часы сервиса; в тестах подменяются, чтобы закрепить дату
+var clock Clock = realClock{}
+
+// Системное время
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// Генератор случайных чисел для выбора поздравлений без seed; в тестах
+// подменяется генератором с фиксированным seed
+var random = rand.New(newLockedSource(time.Now().UnixNano()))
+
+// Источник для random: *rand.Rand сам по себе не безопасен для горутин
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func newLockedSource(seed int64) *lockedSource {
+	return &lockedSource{src: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Часы, которые показывают заданное время и двигаются только вручную
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Подменяет часы сервиса на время now до конца теста
+func useFakeClock(t *testing.T, now time.Time) *fakeClock {
+	t.Helper()
+	fake := &fakeClock{now: now}
+	saved := clock
+	clock = fake
+	t.Cleanup(func() { clock = saved })
+	return fake
+}
+
+// birth_day поздравления периода по запросу greetingOfThePeriod
+func greetingOfThePeriod(t *testing.T, period string) int {
+	t.Helper()
+	result := execQuery(t, `query($period: Period!) { greetingOfThePeriod(period: $period) { birth_day } }`,
+		map[string]interface{}{"period": period})
+	if len(result.Errors) > 0 {
+		t.Fatalf("greetingOfThePeriod(%s): %v", period, result.Errors)
+	}
+	return result.Data.(map[string]interface{})["greetingOfThePeriod"].(map[string]interface{})["birth_day"].(int)
+}
+
+func TestGreetingOfThePeriodPinnedClock(t *testing.T) {
+	fake := useFakeClock(t, date("2025-03-08 09:00"))
+	pool := allGreetings()
+
+	for _, period := range []string{periodDay, periodWeek, periodMonth} {
+		want := selectForPeriod(pool, period, date("2025-03-08 00:00")).BirthDay
+		if got := greetingOfThePeriod(t, period); got != want {
+			t.Errorf("%s на 8 марта: %d, ожидалось %d", period, got, want)
+		}
+	}
+
+	// В пределах той же недели (вс 9 марта) неделя и месяц не меняются
+	week, month := greetingOfThePeriod(t, periodWeek), greetingOfThePeriod(t, periodMonth)
+	fake.Set(date("2025-03-09 23:00"))
+	if got := greetingOfThePeriod(t, periodWeek); got != week {
+		t.Errorf("WEEK в воскресенье той же недели: %d, ожидалось %d", got, week)
+	}
+	if got := greetingOfThePeriod(t, periodMonth); got != month {
+		t.Errorf("MONTH 9 марта: %d, ожидалось %d", got, month)
+	}
+
+	// Со следующей недели выбор следует новому ключу периода
+	fake.Advance(2 * time.Hour)
+	want := selectForPeriod(pool, periodWeek, date("2025-03-10 00:00")).BirthDay
+	if got := greetingOfThePeriod(t, periodWeek); got != want {
+		t.Errorf("WEEK 10 марта: %d, ожидалось %d", got, want)
+	}
+}
+
+func TestDailyStrategyPinnedClock(t *testing.T) {
+	fake := useFakeClock(t, date("2025-03-08 00:01"))
+	pool := allGreetings()
+	daily := dailyStrategy{}
+
+	first := daily.Select(pool)
+	fake.Set(date("2025-03-08 23:59"))
+	if got := daily.Select(pool); got != first {
+		t.Errorf("в течение дня выбор изменился: %d и %d", first.BirthDay, got.BirthDay)
+	}
+	if want := selectForPeriod(pool, periodDay, date("2025-03-08 12:00")); first != want {
+		t.Errorf("daily = %d, а поздравление дня — %d", first.BirthDay, want.BirthDay)
+	}
+
+	seen := map[int]bool{first.BirthDay: true}
+	for i := 0; i < 10; i++ {
+		fake.Advance(24 * time.Hour)
+		seen[daily.Select(pool).BirthDay] = true
+	}
+	if len(seen) < 2 {
+		t.Error("за 11 дней daily выбрал одно и то же поздравление")
+	}
+}
+
+func TestResponseCacheExpiresByClock(t *testing.T) {
+	fake := useFakeClock(t, date("2025-03-08 12:00"))
+	cache := newResponseCache(time.Minute, 10)
+	cache.put("k", []byte("v"), nil)
+
+	fake.Advance(59 * time.Second)
+	if _, ok := cache.get("k"); !ok {
+		t.Error("запись истекла раньше TTL")
+	}
+	fake.Advance(2 * time.Second)
+	if _, ok := cache.get("k"); ok {
+		t.Error("запись не истекла после TTL")
+	}
+}
+
+func TestWindowLimiterResetsByClock(t *testing.T) {
+	fake := useFakeClock(t, date("2025-03-08 12:00"))
+	limiter := newWindowLimiter(1, time.Minute)
+
+	if ok, _ := limiter.allow("a"); !ok {
+		t.Fatal("первый запрос отклонён")
+	}
+	ok, retryAfter := limiter.allow("a")
+	if ok || retryAfter != time.Minute {
+		t.Errorf("второй запрос: ok=%v, retryAfter=%s; ожидался отказ на 1m", ok, retryAfter)
+	}
+	fake.Advance(time.Minute)
+	if ok, _ := limiter.allow("a"); !ok {
+		t.Error("в новом окне запрос отклонён")
+	}
+}
+
+// Подменяет общий генератор random генератором с фиксированным seed до конца теста
+func useSeededRandom(t *testing.T, seed int64) {
+	t.Helper()
+	saved := random
+	random = rand.New(newLockedSource(seed))
+	t.Cleanup(func() { random = saved })
+}
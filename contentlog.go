@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Включается CONTENT_LOG=true: по строке JSON на каждое выданное поздравление
+var contentLogEnabled bool
+
+// Событие для контентной аналитики
+type contentEvent struct {
+	Event    string    `json:"event"`
+	Time     time.Time `json:"time"`
+	BirthDay int       `json:"birth_day,omitempty"`
+	Reason   string    `json:"reason"`
+	Cached   bool      `json:"cached,omitempty"`
+}
+
+// События, записанные за время одного запроса; нужны кэшу ответов,
+// чтобы повторить их, когда ответ отдаётся без вызова резолверов
+type contentEventRecorder struct {
+	mu     sync.Mutex
+	events []contentEvent
+}
+
+type contentEventRecorderKey struct{}
+
+func withContentEventRecorder(ctx context.Context) (context.Context, *contentEventRecorder) {
+	rec := &contentEventRecorder{}
+	return context.WithValue(ctx, contentEventRecorderKey{}, rec), rec
+}
+
+func (rec *contentEventRecorder) recorded() []contentEvent {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]contentEvent(nil), rec.events...)
+}
+
+// Записывает событие выдачи поздравления, если контентный лог включён.
+// reason — почему выбрано именно это поздравление (по birth_day, стратегией и т.д.).
+func logGreetingResolved(ctx context.Context, birthDay int, reason string) {
+	if !contentLogEnabled {
+		return
+	}
+	event := contentEvent{
+		Event:    "greeting_resolved",
+		Time:     clock.Now().UTC(),
+		BirthDay: birthDay,
+		Reason:   reason,
+	}
+	if rec, ok := ctx.Value(contentEventRecorderKey{}).(*contentEventRecorder); ok {
+		rec.mu.Lock()
+		rec.events = append(rec.events, event)
+		rec.mu.Unlock()
+	}
+	writeContentEvent(event)
+}
+
+// Повторяет события ответа, отданного из кэша
+func logCachedGreetings(events []contentEvent) {
+	if !contentLogEnabled {
+		return
+	}
+	for _, event := range events {
+		event.Time = clock.Now().UTC()
+		event.Cached = true
+		writeContentEvent(event)
+	}
+}
+
+// Пишет событие в вывод стандартного логгера (stderr или LOG_FILE) без префикса,
+// чтобы каждая строка была валидным JSON и не смешивалась с выводом CLI
+func writeContentEvent(event contentEvent) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("ошибка записи контентного лога: %v", err)
+		return
+	}
+	fmt.Fprintln(log.Writer(), string(line))
+}
@@ -0,0 +1,27 @@
+package main
+
+// Коды ошибок, передаваемые клиентам в extensions.code
+const (
+	codeInvalidID        = "INVALID_ID"
+	codeValidationFailed = "VALIDATION_FAILED"
+	codeNoMatch          = "NO_MATCH"
+	codeBlocked          = "BLOCKED"
+)
+
+// Ошибка с машиночитаемым кодом; graphql-go выводит его в extensions ответа
+type codedError struct {
+	code    string
+	message string
+}
+
+func newCodedError(code, message string) *codedError {
+	return &codedError{code: code, message: message}
+}
+
+func (e *codedError) Error() string {
+	return e.message
+}
+
+func (e *codedError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// Буферизует ответ, чтобы выбрать HTTP-статус после того, как известен результат
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header         { return b.header }
+func (b *bufferedResponse) WriteHeader(status int)      { b.status = status }
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+// Подбирает HTTP-статус по ошибкам GraphQL-результата:
+//   - ошибки без кода при data == null (синтаксис, валидация запроса) — 400;
+//   - хотя бы один VALIDATION_FAILED — 400;
+//   - все ошибки INVALID_ID, NO_MATCH или BLOCKED и нет ни одного непустого поля — 404;
+//   - остальное (в том числе частичный результат) — 200.
+func statusForResult(body []byte) int {
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Extensions struct {
+				Code string `json:"code"`
+			} `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || len(result.Errors) == 0 {
+		return http.StatusOK
+	}
+
+	notFound := true
+	for _, e := range result.Errors {
+		switch e.Extensions.Code {
+		case codeValidationFailed:
+			return http.StatusBadRequest
+		case codeInvalidID, codeNoMatch, codeBlocked:
+		case "":
+			if len(result.Data) == 0 || string(result.Data) == "null" {
+				return http.StatusBadRequest
+			}
+			notFound = false
+		default:
+			notFound = false
+		}
+	}
+	if notFound && !hasData(result.Data) {
+		return http.StatusNotFound
+	}
+	return http.StatusOK
+}
+
+// Есть ли в data хотя бы одно непустое поле верхнего уровня
+func hasData(data json.RawMessage) bool {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false
+	}
+	for _, value := range fields {
+		if string(value) != "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// Отвечает HTTP-статусом, соответствующим ошибкам результата, вместо всегда 200.
+// Включается HTTP_ERROR_STATUS=true; по умолчанию сервер следует спецификации GraphQL over HTTP.
+func mapErrorStatus(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isBrowserRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		buf := &bufferedResponse{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+		status := buf.status
+		if status == http.StatusOK {
+			status = statusForResult(buf.body.Bytes())
+		}
+		w.WriteHeader(status)
+		w.Write(buf.body.Bytes())
+	})
+}
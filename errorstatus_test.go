@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestStatusForResult(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"без ошибок", `{"data":{"greeting":{"text":"..."}}}`, http.StatusOK},
+		{"ошибка синтаксиса", `{"data":null,"errors":[{"message":"Syntax Error"}]}`, http.StatusBadRequest},
+		{"VALIDATION_FAILED", `{"data":{"fitsInWidth":null},"errors":[{"message":"...","extensions":{"code":"VALIDATION_FAILED"}}]}`, http.StatusBadRequest},
+		{"INVALID_ID", `{"data":{"greeting":null},"errors":[{"message":"...","extensions":{"code":"INVALID_ID"}}]}`, http.StatusNotFound},
+		{"NO_MATCH", `{"data":{"randomGreeting":null},"errors":[{"message":"...","extensions":{"code":"NO_MATCH"}}]}`, http.StatusNotFound},
+		{"BLOCKED", `{"data":{"greeting":null},"errors":[{"message":"...","extensions":{"code":"BLOCKED"}}]}`, http.StatusNotFound},
+		{"частичный результат", `{"data":{"a":null,"b":{"text":"..."}},"errors":[{"message":"...","extensions":{"code":"INVALID_ID"}}]}`, http.StatusOK},
+		{"неизвестный код", `{"data":{"greeting":null},"errors":[{"message":"...","extensions":{"code":"OTHER"}}]}`, http.StatusOK},
+		{"ошибка без кода при data", `{"data":{"greeting":null},"errors":[{"message":"..."}]}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if got := statusForResult([]byte(tt.body)); got != tt.want {
+			t.Errorf("%s: статус %d, ожидался %d", tt.name, got, tt.want)
+		}
+	}
+}
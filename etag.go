@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/handler"
+)
+
+// Состоит ли документ только из запросов к служебным полям (__schema, __type, __typename)
+func isIntrospectionOnly(doc *ast.Document) bool {
+	found := false
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if op.Operation != ast.OperationTypeQuery || op.SelectionSet == nil {
+			return false
+		}
+		for _, sel := range op.SelectionSet.Selections {
+			field, ok := sel.(*ast.Field)
+			if !ok || !strings.HasPrefix(field.Name.Value, "__") {
+				return false
+			}
+		}
+		found = true
+	}
+	return found
+}
+
+// ETag ответа на интроспекцию: версия схемы и хеш нормализованного запроса
+// с переменными, так как разные запросы интроспекции дают разные ответы.
+// Со сменой schemaVersion все выданные ранее ETag становятся недействительными.
+func introspectionETag(doc *ast.Document, opts *handler.RequestOptions) (string, bool) {
+	variables, err := json.Marshal(opts.Variables)
+	if err != nil {
+		return "", false
+	}
+	normalized, _ := printer.Print(doc).(string)
+	h := fnv.New64a()
+	h.Write([]byte(normalized + "\x00" + string(variables) + "\x00" + opts.OperationName))
+	return fmt.Sprintf(`"%s-%x"`, schemaVersion, h.Sum64()), true
+}
+
+// Совпадает ли etag с одним из значений If-None-Match
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Условные запросы к интроспекции: ETag на ответ и 304 при совпадающем If-None-Match.
+// Остальные запросы передаются дальше без изменений.
+func conditionalIntrospection(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isBrowserRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Тело читается только у POST: для остальных методов graphql-go/handler
+		// берёт запрос из параметров URL
+		var raw []byte
+		if r.Method == http.MethodPost && r.Body != nil {
+			var err error
+			raw, err = io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "не удалось прочитать тело запроса", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(raw))
+		}
+		opts := handler.NewRequestOptions(r)
+		if r.Method == http.MethodPost && r.Body != nil {
+			r.Body = io.NopCloser(bytes.NewReader(raw))
+		}
+
+		// Без "__" в тексте интроспекции быть не может, разбирать запрос незачем
+		if !strings.Contains(opts.Query, "__") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		doc, err := parser.Parse(parser.ParseParams{Source: opts.Query})
+		if err != nil || !isIntrospectionOnly(doc) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		etag, ok := introspectionETag(doc, opts)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		// If-None-Match проверяется только для GET и HEAD: для остальных методов
+		// RFC 9110 §13.1.2 требует 412 вместо 304, а POST-клиенты его не ждут.
+		// ETag на POST всё равно отдаётся, чтобы клиент мог перейти на GET.
+		if (r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+			etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
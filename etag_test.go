@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const typenameQuery = `{ __typename }`
+
+func getQuery(h http.Handler, query, ifNoneMatch string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/graphql?"+url.Values{"query": {query}}.Encode(), nil)
+	if ifNoneMatch != "" {
+		r.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestIntrospectionNotModified(t *testing.T) {
+	next := &countingHandler{}
+	h := conditionalIntrospection(next)
+
+	first := getQuery(h, typenameQuery, "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("статус %d, ETag %q", first.Code, etag)
+	}
+
+	second := getQuery(h, typenameQuery, etag)
+	if second.Code != http.StatusNotModified {
+		t.Errorf("статус %d, ожидался 304", second.Code)
+	}
+	if next.calls != 1 {
+		t.Errorf("обработчик вызван %d раз, ожидался 1", next.calls)
+	}
+}
+
+func TestIntrospectionPostNeverNotModified(t *testing.T) {
+	h := conditionalIntrospection(&countingHandler{})
+	etag := postQuery(h, typenameQuery).Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("на POST не выдан ETag")
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"`+typenameQuery+`"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("If-None-Match", etag)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	if rec.Code != http.StatusOK {
+		t.Errorf("статус %d, POST должен отвечать 200", rec.Code)
+	}
+}
+
+func TestNoETagForRegularQuery(t *testing.T) {
+	h := conditionalIntrospection(&countingHandler{})
+	for _, query := range []string{`{ greetings { text } }`, `{ __typename greetings { text } }`} {
+		if etag := getQuery(h, query, "").Header().Get("ETag"); etag != "" {
+			t.Errorf("%s: ETag %q у запроса не только к интроспекции", query, etag)
+		}
+	}
+}
+
+func TestIntrospectionETagCarriesSchemaVersion(t *testing.T) {
+	h := conditionalIntrospection(&countingHandler{})
+	etag := getQuery(h, typenameQuery, "").Header().Get("ETag")
+	if !strings.HasPrefix(etag, `"`+schemaVersion+"-") {
+		t.Errorf("ETag %q не начинается с версии схемы %s", etag, schemaVersion)
+	}
+}
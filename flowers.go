@@ -0,0 +1,121 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const zeroWidthJoiner = '\u200d'
+
+// Названия цветов для доступного текстового описания
+var flowerNameByEmoji = map[string]string{
+	"🌷": "tulip",
+	"🌹": "rose",
+	"🌸": "cherry blossom",
+	"🌼": "blossom",
+	"🌻": "sunflower",
+	"🌺": "hibiscus",
+	"💐": "bouquet",
+}
+
+// Цветы для поздравлений без цветов при useDefaultFlowers (задаются DEFAULT_FLOWERS)
+var defaultFlowers = "💐"
+
+// Название для эмодзи, которого нет в flowerNameByEmoji (задаётся FLOWER_FALLBACK_NAME)
+var flowerFallbackName = "flower"
+
+// Неизвестные эмодзи, о которых уже написали в лог
+var loggedUnknownFlowers sync.Map
+
+// Возвращает названия цветов по порядку, по одному на каждое вхождение эмодзи
+// (повторы сохраняются: "🌷🌷🌷" даёт три "tulip")
+func flowerNames(flowers string) []string {
+	names := []string{}
+	for _, emoji := range splitFlowers(flowers) {
+		names = append(names, flowerName(emoji))
+	}
+	return names
+}
+
+// Название одного эмодзи; неизвестные заменяются flowerFallbackName
+// и один раз логируются, чтобы словарь можно было дополнить
+func flowerName(emoji string) string {
+	if name, ok := flowerNameByEmoji[emoji]; ok {
+		return name
+	}
+	if _, seen := loggedUnknownFlowers.LoadOrStore(emoji, struct{}{}); !seen {
+		log.Printf("неизвестный эмодзи цветка %q (%U), используется %q", emoji, []rune(emoji), flowerFallbackName)
+	}
+	return flowerFallbackName
+}
+
+// Поздравления, в цветах которых есть emoji (сравнение по графемным кластерам)
+func greetingsContaining(emoji string) []GreetingResponse {
+	result := []GreetingResponse{}
+	for _, greeting := range allGreetings() {
+		for _, flower := range splitFlowers(greeting.Flowers) {
+			if flower == emoji {
+				result = append(result, greeting)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Разбивает строку цветов на отдельные эмодзи (графемные кластеры).
+// Модификаторы (вариационные селекторы, тона кожи, теги, комбинируемые знаки)
+// и последовательности, склеенные ZWJ, остаются в одном кластере,
+// пара региональных индикаторов образует один флаг.
+// Пустая строка даёт пустой (не nil) список.
+func splitFlowers(s string) []string {
+	clusters := []string{}
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		j := i + 1
+		if isRegionalIndicator(runes[i]) && j < len(runes) && isRegionalIndicator(runes[j]) {
+			j++
+		}
+		for j < len(runes) {
+			if isEmojiExtender(runes[j]) {
+				j++
+				continue
+			}
+			if runes[j] == zeroWidthJoiner {
+				j++
+				if j < len(runes) {
+					j++
+				}
+				continue
+			}
+			break
+		}
+		clusters = append(clusters, string(runes[i:j]))
+		i = j
+	}
+	return clusters
+}
+
+// Начинается ли кластер с символа-пиктограммы (категория Unicode So)
+func isEmoji(cluster string) bool {
+	r, _ := utf8.DecodeRuneInString(cluster)
+	return unicode.Is(unicode.So, r)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func isEmojiExtender(r rune) bool {
+	switch {
+	case r >= 0xFE00 && r <= 0xFE0F: // вариационные селекторы
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF: // модификаторы тона кожи
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // теговые символы
+		return true
+	}
+	return unicode.In(r, unicode.Mn, unicode.Me)
+}
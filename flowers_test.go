@@ -0,0 +1,96 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlowerNamesKeepsRepeats(t *testing.T) {
+	got := flowerNames("🌷🌷🌷")
+	want := []string{"tulip", "tulip", "tulip"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("flowerNames(🌷🌷🌷) = %q, ожидалось %q", got, want)
+	}
+}
+
+func TestFlowerNamesUnknownEmojiFallback(t *testing.T) {
+	got := flowerNames("🌷🪻")
+	want := []string{"tulip", "flower"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("flowerNames(🌷🪻) = %q, ожидалось %q", got, want)
+	}
+
+	defer func(name string) { flowerFallbackName = name }(flowerFallbackName)
+	flowerFallbackName = "цветок"
+	if got := flowerNames("🪻"); !reflect.DeepEqual(got, []string{"цветок"}) {
+		t.Errorf("flowerNames(🪻) с FLOWER_FALLBACK_NAME = %q, ожидалось [цветок]", got)
+	}
+}
+
+func TestEmptyFlowers(t *testing.T) {
+	if got := splitFlowers(""); got == nil || len(got) != 0 {
+		t.Errorf("splitFlowers(\"\") = %#v, ожидался пустой срез", got)
+	}
+
+	defer func(saved string) { flowers[0] = saved }(flowers[0])
+	flowers[0] = ""
+	result := execQuery(t, `{ greeting(birth_day: 1) { flowers flowerList flowerNames flowersCount } }`, nil)
+	if len(result.Errors) > 0 {
+		t.Fatalf("ошибки: %v", result.Errors)
+	}
+	greeting := result.Data.(map[string]interface{})["greeting"].(map[string]interface{})
+	want := map[string]interface{}{
+		"flowers":      "",
+		"flowerList":   []interface{}{},
+		"flowerNames":  []interface{}{},
+		"flowersCount": 0,
+	}
+	if !reflect.DeepEqual(greeting, want) {
+		t.Errorf("получено %#v, ожидалось %#v", greeting, want)
+	}
+}
+
+func TestFlowersSeparator(t *testing.T) {
+	result := execQuery(t, `{ greeting(birth_day: 3) { spaced: flowers(flowersSeparator: " ") plain: flowers } }`, nil)
+	if len(result.Errors) > 0 {
+		t.Fatalf("ошибки: %v", result.Errors)
+	}
+	greeting := result.Data.(map[string]interface{})["greeting"].(map[string]interface{})
+	if greeting["spaced"] != "🌷 🌷 🌷" {
+		t.Errorf("flowers(flowersSeparator: \" \") = %q, ожидалось %q", greeting["spaced"], "🌷 🌷 🌷")
+	}
+	if greeting["plain"] != "🌷🌷🌷" {
+		t.Errorf("flowers = %q, ожидалось %q", greeting["plain"], "🌷🌷🌷")
+	}
+
+	result = execQuery(t, `{ greeting(birth_day: 3) { flowers(flowersSeparator: "слишком длинный") } }`, nil)
+	if len(result.Errors) != 1 || result.Errors[0].Extensions["code"] != codeValidationFailed {
+		t.Errorf("длинный разделитель: ошибки %v, ожидался %s", result.Errors, codeValidationFailed)
+	}
+}
+
+func TestUseDefaultFlowers(t *testing.T) {
+	defer func(saved string) { flowers[0] = saved }(flowers[0])
+	flowers[0] = ""
+	defer func(saved string) { defaultFlowers = saved }(defaultFlowers)
+	defaultFlowers = "🌷🌹"
+
+	tests := []struct {
+		query, want string
+	}{
+		{`{ greeting(birth_day: 1, useDefaultFlowers: true) { flowers } }`, "🌷🌹"},
+		{`{ greeting(birth_day: 1, useDefaultFlowers: false) { flowers } }`, ""},
+		{`{ greeting(birth_day: 1) { flowers } }`, ""},
+		{`{ greeting(birth_day: 2, useDefaultFlowers: true) { flowers } }`, flowers[1]},
+	}
+	for _, tt := range tests {
+		result := execQuery(t, tt.query, nil)
+		if len(result.Errors) > 0 {
+			t.Fatalf("%s: %v", tt.query, result.Errors)
+		}
+		got := result.Data.(map[string]interface{})["greeting"].(map[string]interface{})["flowers"]
+		if got != tt.want {
+			t.Errorf("%s: flowers = %q, ожидалось %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -5,4 +5,7 @@ go 1.24.1
 require (
 	github.com/graphql-go/graphql v0.8.1
 	github.com/graphql-go/handler v0.2.4
+	golang.org/x/term v0.36.0
 )
+
+require golang.org/x/sys v0.37.0 // indirect
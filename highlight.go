@@ -0,0 +1,49 @@
+package main
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Оборачивает все вхождения query в text маркерами open/close.
+// Сравнение без учёта регистра по рунам, поэтому работает и для кириллицы.
+// Если маркеры — HTML-теги, остальной текст экранируется.
+func highlight(text, query, open, close string) string {
+	escape := func(s string) string { return s }
+	if strings.HasPrefix(open, "<") {
+		escape = html.EscapeString
+	}
+
+	textRunes := []rune(text)
+	queryRunes := []rune(query)
+	if len(queryRunes) == 0 {
+		return escape(text)
+	}
+
+	var b strings.Builder
+	start := 0 // начало ещё не выведенного участка
+	for i := 0; i+len(queryRunes) <= len(textRunes); {
+		if !equalFoldRunes(textRunes[i:i+len(queryRunes)], queryRunes) {
+			i++
+			continue
+		}
+		b.WriteString(escape(string(textRunes[start:i])))
+		b.WriteString(open)
+		b.WriteString(escape(string(textRunes[i : i+len(queryRunes)])))
+		b.WriteString(close)
+		i += len(queryRunes)
+		start = i
+	}
+	b.WriteString(escape(string(textRunes[start:])))
+	return b.String()
+}
+
+func equalFoldRunes(a, b []rune) bool {
+	for i := range a {
+		if unicode.ToLower(a[i]) != unicode.ToLower(b[i]) {
+			return false
+		}
+	}
+	return true
+}
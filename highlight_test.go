@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text, query, open, close, want string
+	}{
+		{"Весна, весна и ВЕСНА!", "весна", "<mark>", "</mark>",
+			"<mark>Весна</mark>, <mark>весна</mark> и <mark>ВЕСНА</mark>!"},
+		{"аааа", "аа", "[", "]", "[аа][аа]"},
+		{"Цветы & <любовь>", "любовь", "<mark>", "</mark>", "Цветы &amp; &lt;<mark>любовь</mark>&gt;"},
+		{"Цветы & любовь", "цветы", "**", "**", "**Цветы** & любовь"},
+		{"С 8 Марта!", "осень", "<mark>", "</mark>", "С 8 Марта!"},
+		{"<b>", "", "<mark>", "</mark>", "&lt;b&gt;"},
+	}
+	for _, tt := range tests {
+		if got := highlight(tt.text, tt.query, tt.open, tt.close); got != tt.want {
+			t.Errorf("highlight(%q, %q) = %q, ожидалось %q", tt.text, tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Публичный адрес сервиса (PUBLIC_BASE_URL), например https://greetings.example.com.
+// Пустой — адрес берётся из запроса.
+var publicBaseURL *url.URL
+
+// Проверяет и разбирает PUBLIC_BASE_URL: нужна схема http или https и хост
+func parsePublicBaseURL(value string) (*url.URL, error) {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("некорректный PUBLIC_BASE_URL %q: ожидается адрес вида https://example.com", value)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("PUBLIC_BASE_URL %q не должен содержать query или fragment", value)
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	return u, nil
+}
+
+// Абсолютная ссылка на path. Без PUBLIC_BASE_URL схема и хост берутся из запроса
+// (X-Forwarded-Proto учитывается, если сервис стоит за прокси).
+func absoluteURL(r *http.Request, path string) string {
+	base := publicBaseURL
+	if base == nil {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		base = &url.URL{Scheme: scheme, Host: r.Host}
+	}
+	u := *base
+	u.Path = base.Path + "/" + strings.TrimLeft(path, "/")
+	return u.String()
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// Файл лога с ротацией по размеру: при превышении maxBytes текущий файл
+// переименовывается в <path>.1 (предыдущая копия затирается) и открывается новый
+type rotatingFile struct {
+	path     string
+	maxBytes int64
+	file     *os.File
+	size     int64
+}
+
+func openRotatingFile(path string, maxBytes int64) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, maxBytes: maxBytes}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть файл лога %s: %v", f.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("не удалось прочитать файл лога %s: %v", f.path, err)
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	if f.size > 0 && f.size+int64(len(p)) > f.maxBytes {
+		f.file.Close()
+		if err := os.Rename(f.path, f.path+".1"); err != nil {
+			return 0, err
+		}
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) Close() error {
+	return f.file.Close()
+}
+
+// Пишет в w из отдельной горутины, чтобы медленный диск не задерживал запросы.
+// Если очередь переполнена, запись отбрасывается и учитывается в dropped.
+type asyncWriter struct {
+	w       io.WriteCloser
+	queue   chan []byte
+	done    chan struct{}
+	once    sync.Once
+	dropped atomic.Int64
+}
+
+func newAsyncWriter(w io.WriteCloser, queueSize int) *asyncWriter {
+	a := &asyncWriter{w: w, queue: make(chan []byte, queueSize), done: make(chan struct{})}
+	go func() {
+		defer close(a.done)
+		for p := range a.queue {
+			a.w.Write(p)
+		}
+	}()
+	return a
+}
+
+func (a *asyncWriter) Write(p []byte) (int, error) {
+	// log переиспользует буфер, поэтому нужна копия
+	buf := append([]byte(nil), p...)
+	select {
+	case a.queue <- buf:
+	default:
+		a.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Дописывает оставшиеся строки и закрывает файл
+func (a *asyncWriter) Close() error {
+	a.once.Do(func() { close(a.queue) })
+	<-a.done
+	if n := a.dropped.Load(); n > 0 {
+		fmt.Fprintf(os.Stderr, "в файл лога не записано строк из-за переполнения очереди: %d\n", n)
+	}
+	return a.w.Close()
+}
@@ -1,19 +1,15 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	"github.com/graphql-go/graphql"
-	"github.com/graphql-go/handler"
+	"golang.org/x/term"
 )
 
 // Список поздравлений (индекс 0 соответствует birth_day 1 и т.д.)
@@ -66,150 +62,139 @@ var flowers = []string{
 
 // Структура, представляющая ответ с поздравлением и цветами
 type GreetingResponse struct {
-	Text    string `json:"text"`
-	Flowers string `json:"flowers"`
+	BirthDay int    `json:"birth_day"`
+	Text     string `json:"text"`
+	Flowers  string `json:"flowers"`
+	// Как выбрано поздравление (стратегия, seed, период); пусто при выборе по birth_day
+	SelectionReason string `json:"selectionReason,omitempty"`
 }
 
-func main() {
-	// 1. Определяем объектный тип Greeting
-	greetingType := graphql.NewObject(graphql.ObjectConfig{
-		Name: "Greeting",
-		Fields: graphql.Fields{
-			"text": &graphql.Field{
-				Type: graphql.NewNonNull(graphql.String),
-			},
-			"flowers": &graphql.Field{
-				Type: graphql.NewNonNull(graphql.String),
-			},
-		},
-	})
-
-	// 2. Поле greeting в корневом запросе
-	greetingField := &graphql.Field{
-		Type: greetingType,
-		Args: graphql.FieldConfigArgument{
-			"birth_day": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(graphql.Int),
-			},
-		},
-		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-			birth_day, ok := p.Args["birth_day"].(int)
-			if !ok {
-				return nil, fmt.Errorf("birth_day должен быть целым числом")
-			}
-			if birth_day < 1 || birth_day > len(greetings) {
-				return nil, fmt.Errorf("поздравление для birth_day %d не найдено", birth_day)
-			}
-			// Индексация с 0
-			return GreetingResponse{
-				Text:    greetings[birth_day-1],
-				Flowers: flowers[birth_day-1],
-			}, nil
-		},
+// Возвращает поздравление по аргументу birth_day
+func greetingFromArgs(args map[string]interface{}) (GreetingResponse, error) {
+	birth_day, ok := args["birth_day"].(int)
+	if !ok {
+		return GreetingResponse{}, newCodedError(codeInvalidID, "birth_day должен быть целым числом")
+	}
+	// Отрицательные, нулевые и слишком большие значения (вплоть до MaxInt32) отсекаются здесь
+	if birth_day < 1 || birth_day > len(greetings) {
+		return GreetingResponse{}, newCodedError(codeInvalidID, fmt.Sprintf("поздравление для birth_day %d не найдено", birth_day))
 	}
+	if isBlocked(birth_day) {
+		return GreetingResponse{}, newCodedError(codeBlocked, fmt.Sprintf("поздравление для birth_day %d временно недоступно", birth_day))
+	}
+	return greetingAt(birth_day), nil
+}
 
-	rootQuery := graphql.NewObject(graphql.ObjectConfig{
-		Name: "Query",
-		Fields: graphql.Fields{
-			"greeting": greetingField,
-		},
-	})
+// Поздравление по заведомо корректному birth_day (от 1 до len(greetings))
+func greetingAt(birth_day int) GreetingResponse {
+	// Индексация с 0
+	return GreetingResponse{
+		BirthDay: birth_day,
+		Text:     greetings[birth_day-1],
+		Flowers:  flowers[birth_day-1],
+	}
+}
 
-	schemaConfig := graphql.SchemaConfig{Query: rootQuery}
-	schema, err := graphql.NewSchema(schemaConfig)
-	if err != nil {
-		log.Fatalf("ошибка создания схемы GraphQL: %v", err)
+// Использование:
+//
+//	greeting [-interactive=false] [-quiet]  — сервер и интерактивный CLI в одном процессе (по умолчанию)
+//	greeting serve [-quiet]                 — только GraphQL-сервер
+//	greeting query [-server URL] [-quiet]   — только CLI-клиент к уже запущенному серверу
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "query":
+			runQuery(os.Args[2:])
+			return
+		}
 	}
+	runCombined(os.Args[1:])
+}
 
-	// 3. Создаём HTTP-обработчик с включённым GraphiQL
-	graphqlHandler := handler.New(&handler.Config{
-		Schema:   &schema,
-		Pretty:   true,
-		GraphiQL: true,
-	})
+// Подкоманда serve: только сервер, работает до SIGINT/SIGTERM
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	quiet := fs.Bool("quiet", false, "не выводить информационные сообщения запуска и остановки")
+	fs.Parse(args)
 
-	// 4. Определяем порт из окружения или используем 8080 по умолчанию
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	ctx, stop := signalContext()
+	defer stop()
 
-	server := &http.Server{Addr: ":" + port, Handler: graphqlHandler}
-	go func() {
-		log.Printf("GraphQL сервер запущен на http://localhost:%s", port)
-		log.Printf("GraphiQL интерфейс доступен по адресу http://localhost:%s", port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("ошибка запуска сервера: %v", err)
-		}
-	}()
-
-	// 5. Ожидание сигнала завершения
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
-
-	// 6. CLI-взаимодействие
-	fmt.Println("Введите birth_day (от 1 до 31) для получения текста и цветов. Для выхода введите 'exit' или нажмите Ctrl+C.")
-	for {
-		fmt.Print("birth_day: ")
-		var input string
-		_, err := fmt.Scanln(&input)
-		if err != nil {
-			fmt.Println("Ошибка ввода, попробуйте снова")
-			continue
-		}
-		if input == "exit" {
-			fmt.Println("Завершение работы.")
-			break
-		}
+	server, _ := startServer(*quiet)
+	<-ctx.Done()
+	shutdownServer(server, *quiet)
+}
 
-		var birth_day int
-		_, err = fmt.Sscan(input, &birth_day)
-		if err != nil {
-			fmt.Println("Пожалуйста, введите число от 1 до 10")
-			continue
-		}
+// Подкоманда query: интерактивный CLI к серверу по адресу -server
+func runQuery(args []string) {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	serverURL := fs.String("server", "http://localhost:8080/graphql", "адрес GraphQL-эндпоинта сервера")
+	quiet := fs.Bool("quiet", false, "не выводить приветствие и подсказки")
+	fs.Parse(args)
 
-		// Формируем GraphQL-запрос (теперь запрашиваем оба поля)
-		query := fmt.Sprintf(`{"query": "query { greeting(birth_day: %d) { text flowers } }"}`, birth_day)
-		body := bytes.NewBufferString(query)
+	ctx, stop := signalContext()
+	defer stop()
 
-		resp, err := http.Post(fmt.Sprintf("http://localhost:%s/", port), "application/json", body)
-		if err != nil {
-			log.Printf("Ошибка при отправке запроса: %v", err)
-			continue
-		}
-		defer resp.Body.Close()
-
-		var result struct {
-			Data struct {
-				Greeting struct {
-					Text    string `json:"text"`
-					Flowers string `json:"flowers"`
-				} `json:"greeting"`
-			} `json:"data"`
-			Errors []struct {
-				Message string `json:"message"`
-			} `json:"errors"`
+	runCLI(ctx, os.Stdin, os.Stdout, *serverURL, *quiet)
+}
+
+// Режим по умолчанию: сервер и CLI к нему в одном процессе
+func runCombined(args []string) {
+	fs := flag.NewFlagSet("greeting", flag.ExitOnError)
+	interactive := fs.Bool("interactive", true, "запустить интерактивный CLI поверх сервера; false — только сервер")
+	quiet := fs.Bool("quiet", false, "не выводить приветствие, подсказки и информационные сообщения запуска")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Использование: %s [serve|query] [флаги]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	// Общий контекст: по сигналу CLI перестаёт принимать ввод, затем сервер останавливается
+	ctx, stop := signalContext()
+	defer stop()
+
+	// Без явного -interactive и без источника ввода (например, в контейнере,
+	// где stdin — /dev/null) CLI сразу получил бы EOF и остановил сервер,
+	// поэтому работаем как чистый сервер
+	explicit := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "interactive" {
+			explicit = true
 		}
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			log.Printf("Ошибка декодирования ответа: %v", err)
-			continue
+	})
+	if !explicit && !stdinHasInput(os.Stdin) {
+		*interactive = false
+		if !*quiet {
+			log.Printf("Терминал не обнаружен: режим только сервера, ожидание SIGINT/SIGTERM")
 		}
+	}
 
-		if len(result.Errors) > 0 {
-			fmt.Printf("Ошибка от сервера: %s\n", result.Errors[0].Message)
-		} else {
-			fmt.Printf("Поздравление: %s\n", result.Data.Greeting.Text)
-			fmt.Printf("Цветы: %s\n\n", result.Data.Greeting.Flowers)
-		}
+	server, endpoint := startServer(*quiet)
+	if *interactive {
+		runCLI(ctx, os.Stdin, os.Stdout, endpoint, *quiet)
+	} else {
+		<-ctx.Done()
 	}
+	shutdownServer(server, *quiet)
+}
 
-	// 7. Graceful shutdown
-	fmt.Println("Останавливаем сервер...")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Ошибка при остановке сервера: %v", err)
+// Можно ли читать ввод из f: терминал, канал или обычный файл.
+// Символьные устройства вроде /dev/null и закрытый stdin ввода не дают.
+func stdinHasInput(f *os.File) bool {
+	if term.IsTerminal(int(f.Fd())) {
+		return true
 	}
-	fmt.Println("Сервер остановлен.")
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeNamedPipe != 0 || info.Mode().IsRegular()
+}
+
+// Контекст, отменяемый при получении SIGINT или SIGTERM
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 }
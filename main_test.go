@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Выполняет запрос к схеме сервиса
+func execQuery(t *testing.T, query string, variables map[string]interface{}) *graphql.Result {
+	t.Helper()
+	schema, err := newSchema()
+	if err != nil {
+		t.Fatalf("newSchema: %v", err)
+	}
+	return graphql.Do(graphql.Params{Schema: schema, RequestString: query, VariableValues: variables})
+}
+
+// Код ошибки из extensions.code или "", если ошибка без кода
+func errorCode(err error) string {
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return ""
+}
+
+func TestGreetingFromArgsRejectsOutOfRange(t *testing.T) {
+	for _, birth_day := range []int{-1, 0, len(greetings) + 1, 2147483647} {
+		_, err := greetingFromArgs(map[string]interface{}{"birth_day": birth_day})
+		if code := errorCode(err); code != codeInvalidID {
+			t.Errorf("birth_day %d: ошибка %v с кодом %q, ожидался %s", birth_day, err, code, codeInvalidID)
+		}
+	}
+}
+
+func TestGreetingFromArgsBounds(t *testing.T) {
+	for _, birth_day := range []int{1, len(greetings)} {
+		greeting, err := greetingFromArgs(map[string]interface{}{"birth_day": birth_day})
+		if err != nil {
+			t.Errorf("birth_day %d: %v", birth_day, err)
+			continue
+		}
+		if greeting.BirthDay != birth_day || greeting.Text != greetings[birth_day-1] {
+			t.Errorf("birth_day %d: получено %+v", birth_day, greeting)
+		}
+	}
+}
+
+func TestGreetingQueryRejectsLargeIDs(t *testing.T) {
+	for _, birth_day := range []interface{}{-1, 0, 2147483647, 2147483648} {
+		result := execQuery(t, `query($birth_day: Int!) { greeting(birth_day: $birth_day) { text } }`,
+			map[string]interface{}{"birth_day": birth_day})
+		if len(result.Errors) == 0 {
+			t.Errorf("birth_day %v: ожидалась ошибка, получено %v", birth_day, result.Data)
+		}
+	}
+}
+
+func TestStdinHasInput(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	if stdinHasInput(devNull) {
+		t.Errorf("%s считается источником ввода", os.DevNull)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if !stdinHasInput(r) {
+		t.Error("канал не считается источником ввода")
+	}
+
+	file, err := os.CreateTemp(t.TempDir(), "ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if !stdinHasInput(file) {
+		t.Error("обычный файл не считается источником ввода")
+	}
+
+	closed, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+	if stdinHasInput(closed) {
+		t.Error("закрытый файл считается источником ввода")
+	}
+}
+
+// Без терминала и без флагов процесс работает как сервер и ждёт сигнала,
+// а не завершается сразу на EOF интерактивного цикла
+func TestCombinedWithoutTerminalRunsServerOnly(t *testing.T) {
+	if os.Getenv("GREETING_TEST_COMBINED") == "1" {
+		// Без stdin (exec подставляет /dev/null) и без флагов; os.Exit,
+		// чтобы в stdout не попал вывод самого тестового бинарника
+		runCombined(nil)
+		os.Exit(0)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+	ln.Close()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCombinedWithoutTerminalRunsServerOnly$")
+	cmd.Env = append(os.Environ(), "GREETING_TEST_COMBINED=1", "PORT=127.0.0.1:"+port)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	// Дожидаемся готовности сервера; процесс при этом не должен завершиться
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Get("http://127.0.0.1:" + port + "/readyz")
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		select {
+		case err := <-exited:
+			t.Fatalf("процесс завершился без сигнала: %v\n%s", err, stderr.String())
+		case <-time.After(50 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			cmd.Process.Kill()
+			t.Fatalf("сервер не запустился:\n%s", stderr.String())
+		}
+	}
+	select {
+	case err := <-exited:
+		t.Fatalf("процесс завершился без сигнала: %v\n%s", err, stderr.String())
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	cmd.Process.Signal(syscall.SIGTERM)
+	if err := <-exited; err != nil {
+		t.Fatalf("завершение по SIGTERM: %v\n%s", err, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "режим только сервера") {
+		t.Errorf("в логе нет сообщения о режиме только сервера:\n%s", stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("CLI вывел в stdout:\n%s", stdout.String())
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/handler"
+)
+
+// Реестр именованных операций, загруженных из каталога OPERATIONS_DIR.
+//
+// Структура каталога: файлы *.graphql (без вложенных каталогов), в каждом
+// одна или несколько именованных операций и, при необходимости, фрагменты,
+// которые они используют. Имена операций уникальны в пределах каталога:
+//
+//	operations/
+//	  greeting.graphql   — query GetGreeting($birth_day: Int!) { ... }
+//	  random.graphql     — query Shuffle { ... } и query Surprise { ... }
+//
+// Клиент присылает только operationName и variables. Неизвестное имя — 403.
+// При strict (по умолчанию) запросы с произвольным текстом query тоже
+// отклоняются, без него пропускаются как есть.
+// По SIGHUP каталог перечитывается; при ошибке остаётся прежний набор.
+type operationRegistry struct {
+	dir    string
+	strict bool
+
+	mu         sync.RWMutex
+	operations map[string]string // имя операции -> текст файла с ней
+}
+
+func newOperationRegistry(dir string, strict bool) (*operationRegistry, error) {
+	reg := &operationRegistry{dir: dir, strict: strict}
+	if err := reg.load(); err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
+
+// Перечитывает каталог целиком и атомарно заменяет набор операций
+func (reg *operationRegistry) load() error {
+	files, err := filepath.Glob(filepath.Join(reg.dir, "*.graphql"))
+	if err != nil {
+		return err
+	}
+	operations := make(map[string]string)
+	for _, file := range files {
+		source, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("не удалось прочитать %s: %v", file, err)
+		}
+		doc, err := parser.Parse(parser.ParseParams{Source: string(source)})
+		if err != nil {
+			return fmt.Errorf("ошибка разбора %s: %v", file, err)
+		}
+		for _, def := range doc.Definitions {
+			op, ok := def.(*ast.OperationDefinition)
+			if !ok {
+				continue
+			}
+			if op.Name == nil {
+				return fmt.Errorf("%s: у операции нет имени", file)
+			}
+			if _, dup := operations[op.Name.Value]; dup {
+				return fmt.Errorf("%s: операция %q уже объявлена в другом файле", file, op.Name.Value)
+			}
+			operations[op.Name.Value] = string(source)
+		}
+	}
+
+	reg.mu.Lock()
+	reg.operations = operations
+	reg.mu.Unlock()
+	return nil
+}
+
+func (reg *operationRegistry) lookup(name string) (string, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	source, ok := reg.operations[name]
+	return source, ok
+}
+
+func (reg *operationRegistry) names() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	names := make([]string, 0, len(reg.operations))
+	for name := range reg.operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Перечитывает каталог по SIGHUP
+func (reg *operationRegistry) reloadOnSIGHUP() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := reg.load(); err != nil {
+				log.Printf("операции не перезагружены: %v", err)
+				continue
+			}
+			log.Printf("операции перезагружены из %s: %v", reg.dir, reg.names())
+		}
+	}()
+}
+
+// Подставляет текст зарегистрированной операции по operationName
+func (reg *operationRegistry) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isBrowserRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var opts *handler.RequestOptions
+		if r.Method == http.MethodGet {
+			// graphql-go/handler не разбирает GET без query, поэтому читаем параметры сами
+			q := r.URL.Query()
+			opts = &handler.RequestOptions{Query: q.Get("query"), OperationName: q.Get("operationName")}
+			if vars := q.Get("variables"); vars != "" {
+				if err := json.Unmarshal([]byte(vars), &opts.Variables); err != nil {
+					http.Error(w, "некорректный параметр variables", http.StatusBadRequest)
+					return
+				}
+			}
+		} else {
+			raw, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "не удалось прочитать тело запроса", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(raw))
+			opts = handler.NewRequestOptions(r)
+			r.Body = io.NopCloser(bytes.NewReader(raw))
+		}
+
+		if opts.Query != "" {
+			if reg.strict {
+				http.Error(w, "разрешены только зарегистрированные операции", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		source, ok := reg.lookup(opts.OperationName)
+		if !ok {
+			http.Error(w, fmt.Sprintf("неизвестная операция %q", opts.OperationName), http.StatusForbidden)
+			return
+		}
+		body, err := json.Marshal(handler.RequestOptions{
+			Query:         source,
+			Variables:     opts.Variables,
+			OperationName: opts.OperationName,
+		})
+		if err != nil {
+			http.Error(w, "некорректные variables", http.StatusBadRequest)
+			return
+		}
+		r.Method = http.MethodPost
+		r.URL.RawQuery = ""
+		r.Header.Set("Content-Type", handler.ContentTypeJSON)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		next.ServeHTTP(w, r)
+	})
+}
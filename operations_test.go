@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/handler"
+)
+
+func writeOperation(t *testing.T, dir, name, source string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestRegistry(t *testing.T, strict bool) *operationRegistry {
+	t.Helper()
+	dir := t.TempDir()
+	writeOperation(t, dir, "greeting.graphql", `query GetGreeting($birth_day: Int!) { greeting(birth_day: $birth_day) { text } }`)
+	reg, err := newOperationRegistry(dir, strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reg
+}
+
+func TestOperationsUnknownNameForbidden(t *testing.T) {
+	next := &countingHandler{}
+	h := newTestRegistry(t, false).middleware(next)
+
+	r := httptest.NewRequest(http.MethodGet, "/graphql?operationName=DropAll", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("статус %d, ожидался 403", rec.Code)
+	}
+	if next.calls != 0 {
+		t.Error("неизвестная операция дошла до обработчика")
+	}
+}
+
+func TestOperationsStrictRejectsAdHocQuery(t *testing.T) {
+	for _, strict := range []bool{true, false} {
+		next := &countingHandler{}
+		rec := postQuery(newTestRegistry(t, strict).middleware(next), `{ greetings { text } }`)
+		want := http.StatusOK
+		if strict {
+			want = http.StatusForbidden
+		}
+		if rec.Code != want {
+			t.Errorf("strict=%v: статус %d, ожидался %d", strict, rec.Code, want)
+		}
+	}
+}
+
+func TestOperationsGetIsRewritten(t *testing.T) {
+	var got *handler.RequestOptions
+	var method string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		got = handler.NewRequestOptions(r)
+	})
+	h := newTestRegistry(t, true).middleware(next)
+
+	q := url.Values{"operationName": {"GetGreeting"}, "variables": {`{"birth_day":8}`}}
+	r := httptest.NewRequest(http.MethodGet, "/graphql?"+q.Encode(), nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusOK || got == nil {
+		t.Fatalf("статус %d, запрос не дошёл до обработчика", rec.Code)
+	}
+	if method != http.MethodPost {
+		t.Errorf("метод %s, ожидался POST", method)
+	}
+	if !strings.Contains(got.Query, "query GetGreeting") || got.OperationName != "GetGreeting" {
+		t.Errorf("подставлен неверный запрос: %+v", got)
+	}
+	if want := map[string]interface{}{"birth_day": float64(8)}; !reflect.DeepEqual(got.Variables, want) {
+		t.Errorf("variables %v, ожидалось %v", got.Variables, want)
+	}
+}
+
+func TestOperationsDuplicateNamesFailLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeOperation(t, dir, "a.graphql", `query Same { greetings { text } }`)
+	writeOperation(t, dir, "b.graphql", `query Same { greetings { birth_day } }`)
+	if _, err := newOperationRegistry(dir, true); err == nil {
+		t.Error("ожидалась ошибка из-за повторного имени операции")
+	}
+}
+
+func TestOperationsFailedReloadKeepsPreviousSet(t *testing.T) {
+	reg := newTestRegistry(t, true)
+	writeOperation(t, reg.dir, "broken.graphql", `query Broken {`)
+	if err := reg.load(); err == nil {
+		t.Fatal("ожидалась ошибка разбора")
+	}
+	if names := reg.names(); !reflect.DeepEqual(names, []string{"GetGreeting"}) {
+		t.Errorf("после неудачной перезагрузки набор %v, ожидался [GetGreeting]", names)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// HTML-загрузчик Apollo Sandbox; эндпоинт берётся из текущего адреса страницы
+const apolloSandboxHTML = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="utf-8">
+  <title>Apollo Sandbox</title>
+</head>
+<body style="margin: 0; overflow: hidden;">
+  <div id="embedded-sandbox" style="width: 100%; height: 100vh;"></div>
+  <script src="https://embeddable-sandbox.cdn.apollographql.com/_latest/embeddable-sandbox.umd.production.min.js"></script>
+  <script>
+    new window.EmbeddedSandbox({
+      target: "#embedded-sandbox",
+      initialEndpoint: window.location.origin + window.location.pathname,
+    });
+  </script>
+</body>
+</html>
+`
+
+// Проверяет, что запрос пришёл из браузера (та же логика, что и у GraphiQL в graphql-go/handler)
+func isBrowserRequest(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	_, raw := r.URL.Query()["raw"]
+	return !raw && !strings.Contains(accept, "application/json") && strings.Contains(accept, "text/html")
+}
+
+// Отдаёт Apollo Sandbox браузерным запросам, остальные передаёт GraphQL-обработчику
+func apolloSandbox(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && isBrowserRequest(r) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(apolloSandboxHTML))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Простая страница для браузеров, открывших корень без GraphiQL
+var landingTemplate = template.Must(template.New("landing").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="utf-8">
+  <title>Поздравления с 8 Марта</title>
+</head>
+<body>
+  <h1>🌷 Поздравления с 8 Марта</h1>
+  <p>Это GraphQL API. Отправьте POST-запрос на <code>{{.GraphQL}}</code>, например:</p>
+  <pre>{"query": "{ greeting(birth_day: 8) { text flowers } }"}</pre>
+  <p>Состояние сервиса: <a href="{{.Status}}">{{.Status}}</a></p>
+</body>
+</html>
+`))
+
+// Отвечает браузерам на GET / страницей-заглушкой или редиректом.
+// rootPage: "landing" — страница, "off" — без обработки, иначе адрес для редиректа.
+// Машинные запросы (POST, Accept: application/json) передаются дальше без изменений.
+func rootLanding(next http.Handler, rootPage, graphqlPath string) http.Handler {
+	if rootPage == "off" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" || r.Method != http.MethodGet || r.URL.Query().Get("query") != "" || !isBrowserRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if rootPage != "landing" {
+			http.Redirect(w, r, rootPage, http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		landingTemplate.Execute(w, struct{ GraphQL, Status string }{
+			GraphQL: absoluteURL(r, graphqlPath),
+			Status:  absoluteURL(r, "/status"),
+		})
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Максимальное число поздравлений в одном запросе randomGreetings
+const maxRandomGreetings = 100
+
+// Возвращает count случайных поздравлений; при unique без повторов.
+// rng == nil означает общий генератор random.
+func randomGreetings(rng *rand.Rand, count int, unique bool) ([]GreetingResponse, error) {
+	pool := allGreetings()
+	if err := nonEmptyPool(pool); err != nil {
+		return nil, err
+	}
+	if count < 1 || count > maxRandomGreetings {
+		return nil, newCodedError(codeValidationFailed, fmt.Sprintf("count должен быть от 1 до %d", maxRandomGreetings))
+	}
+	if unique && count > len(pool) {
+		return nil, newCodedError(codeValidationFailed, fmt.Sprintf("без повторов доступно не больше %d поздравлений", len(pool)))
+	}
+	intn := random.Intn
+	if rng != nil {
+		intn = rng.Intn
+	}
+
+	result := make([]GreetingResponse, 0, count)
+	if unique {
+		// Частичная перетасовка Фишера — Йетса: первые count элементов случайны и различны
+		for i := 0; i < count; i++ {
+			j := i + intn(len(pool)-i)
+			pool[i], pool[j] = pool[j], pool[i]
+			result = append(result, pool[i])
+		}
+		return result, nil
+	}
+	for i := 0; i < count; i++ {
+		result = append(result, pool[intn(len(pool))])
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestRandomGreetingsValidation(t *testing.T) {
+	tests := []struct {
+		count  int
+		unique bool
+	}{
+		{0, false},
+		{-1, false},
+		{maxRandomGreetings + 1, false},
+		{len(greetings) + 1, true},
+	}
+	for _, tt := range tests {
+		if _, err := randomGreetings(nil, tt.count, tt.unique); errorCode(err) != codeValidationFailed {
+			t.Errorf("count=%d unique=%v: ошибка %v, ожидался %s", tt.count, tt.unique, err, codeValidationFailed)
+		}
+	}
+}
+
+func TestRandomGreetingsUnique(t *testing.T) {
+	result, err := randomGreetings(nil, len(greetings), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[int]bool{}
+	for _, greeting := range result {
+		if seen[greeting.BirthDay] {
+			t.Fatalf("повтор birth_day %d при unique", greeting.BirthDay)
+		}
+		seen[greeting.BirthDay] = true
+	}
+	if len(seen) != len(greetings) {
+		t.Errorf("различных поздравлений %d, ожидалось %d", len(seen), len(greetings))
+	}
+}
+
+func TestRandomGreetingsSameSeed(t *testing.T) {
+	for _, unique := range []bool{false, true} {
+		first, err := randomGreetings(rand.New(rand.NewSource(42)), 10, unique)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, _ := randomGreetings(rand.New(rand.NewSource(42)), 10, unique)
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("unique=%v: один seed, разные списки", unique)
+		}
+	}
+
+	// Запрос с одинаковым seed через схему
+	const query = `{ randomGreetings(count: 5, seed: 7) { birth_day } }`
+	first, second := execQuery(t, query, nil), execQuery(t, query, nil)
+	if len(first.Errors) > 0 || !reflect.DeepEqual(first.Data, second.Data) {
+		t.Errorf("seed 7: %v и %v (ошибки %v)", first.Data, second.Data, first.Errors)
+	}
+}
+
+func TestRandomGreetingsUnseededUsesPackageRandom(t *testing.T) {
+	useSeededRandom(t, 1)
+	first, _ := randomGreetings(nil, 10, false)
+	useSeededRandom(t, 1)
+	second, _ := randomGreetings(nil, 10, false)
+	if !reflect.DeepEqual(first, second) {
+		t.Error("без seed результат не определяется генератором random")
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// Перехватывает панику в любом обработчике и отвечает JSON с кодом 500
+// вместо обрыва соединения. Оборачивает весь mux, а не только GraphQL.
+func recoverPanics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				// Штатный способ прервать ответ, его обрабатывает net/http
+				panic(rec)
+			}
+			log.Printf("паника при обработке %s %s: %v\n%s", r.Method, r.URL.Path, rec, debug.Stack())
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "внутренняя ошибка сервера"})
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
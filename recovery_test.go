@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestRecoverPanicsReturnsJSON500(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/surprise", func(w http.ResponseWriter, r *http.Request) {
+		panic("сбой обработчика")
+	})
+	srv := httptest.NewServer(recoverPanics(mux))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/surprise")
+	if err != nil {
+		t.Fatalf("соединение оборвано: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("статус %d, ожидался 500", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type %q", ct)
+	}
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body["error"] == "" {
+		t.Errorf("тело не JSON с полем error: %v %v", body, err)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Ремикс двух поздравлений. Он не сохраняется в каталог, поэтому своего
+// birth_day у него нет; sources — birth_day исходных поздравлений.
+type RemixResponse struct {
+	Text    string `json:"text"`
+	Flowers string `json:"flowers"`
+	Sources []int  `json:"sources"`
+}
+
+// Составляет новое поздравление из начала первого и концовки второго.
+// Текст делится на предложения; если какое-то из поздравлений состоит из одного
+// предложения, тексты просто склеиваются. Цветы берутся так же: первая половина
+// от первого поздравления и вторая — от второго.
+func remixGreetings(first, second GreetingResponse) RemixResponse {
+	text := first.Text + " " + second.Text
+	opener := splitSentences(first.Text)
+	closer := splitSentences(second.Text)
+	if len(opener) > 1 && len(closer) > 1 {
+		text = opener[0] + " " + closer[len(closer)-1]
+	}
+
+	firstFlowers := splitFlowers(first.Flowers)
+	secondFlowers := splitFlowers(second.Flowers)
+	flowers := strings.Join(firstFlowers[:(len(firstFlowers)+1)/2], "") +
+		strings.Join(secondFlowers[(len(secondFlowers)+1)/2:], "")
+
+	return RemixResponse{Text: text, Flowers: flowers, Sources: []int{first.BirthDay, second.BirthDay}}
+}
+
+// Делит текст на предложения по знакам . ! ? за которыми следует пробел
+func splitSentences(text string) []string {
+	var sentences []string
+	runes := []rune(text)
+	start := 0
+	for i, r := range runes {
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			continue
+		}
+		if sentence := strings.TrimSpace(string(runes[start : i+1])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = i + 1
+	}
+	if rest := strings.TrimSpace(string(runes[start:])); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
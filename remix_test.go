@@ -0,0 +1,28 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemixGreetingMutation(t *testing.T) {
+	result := execQuery(t, `mutation { remixGreeting(birth_day1: 1, birth_day2: 2) { text flowers sources } }`, nil)
+	if len(result.Errors) > 0 {
+		t.Fatalf("ошибки: %v", result.Errors)
+	}
+	remix := result.Data.(map[string]interface{})["remixGreeting"].(map[string]interface{})
+	want := remixGreetings(greetingAt(1), greetingAt(2))
+	if remix["text"] != want.Text || remix["flowers"] != want.Flowers {
+		t.Errorf("получено %v, ожидалось %+v", remix, want)
+	}
+	if !reflect.DeepEqual(remix["sources"], []interface{}{1, 2}) {
+		t.Errorf("sources = %v, ожидалось [1 2]", remix["sources"])
+	}
+}
+
+func TestRemixGreetingRejectsSave(t *testing.T) {
+	result := execQuery(t, `mutation { remixGreeting(birth_day1: 1, birth_day2: 2, save: true) { text } }`, nil)
+	if len(result.Errors) != 1 || result.Errors[0].Extensions["code"] != codeValidationFailed {
+		t.Errorf("ошибки %v, ожидался %s", result.Errors, codeValidationFailed)
+	}
+}
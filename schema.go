@@ -0,0 +1,378 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Версия GraphQL-схемы по semver, отдаётся запросом schemaVersion.
+// Правила повышения:
+//   - MAJOR — удаление или переименование поля, типа или аргумента,
+//     изменение типа поля, новый обязательный аргумент;
+//   - MINOR — новые поля, типы, необязательные аргументы и значения enum;
+//   - PATCH — изменения описаний и поведения без изменения формы схемы.
+//
+// Версию нужно повышать в том же изменении, что и саму схему.
+const schemaVersion = "2.1.0"
+
+var periodEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "Period",
+	Values: graphql.EnumValueConfigMap{
+		periodDay:   &graphql.EnumValueConfig{Value: periodDay},
+		periodWeek:  &graphql.EnumValueConfig{Value: periodWeek},
+		periodMonth: &graphql.EnumValueConfig{Value: periodMonth},
+	},
+})
+
+// Максимальная длина разделителя цветов в поле flowers
+const maxFlowersSeparatorLen = 8
+
+// Собирает GraphQL-схему сервиса
+func newSchema() (graphql.Schema, error) {
+	// 1. Определяем объектный тип Greeting
+	greetingType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Greeting",
+		Fields: graphql.Fields{
+			"birth_day": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"text": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			// Цветы одной строкой; flowersSeparator вставляется между эмодзи
+			"flowers": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Args: graphql.FieldConfigArgument{
+					"flowersSeparator": &graphql.ArgumentConfig{
+						Type:         graphql.String,
+						DefaultValue: "",
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					greeting := p.Source.(GreetingResponse)
+					separator := p.Args["flowersSeparator"].(string)
+					if utf8.RuneCountInString(separator) > maxFlowersSeparatorLen {
+						return nil, newCodedError(codeValidationFailed, fmt.Sprintf("flowersSeparator не длиннее %d символов", maxFlowersSeparatorLen))
+					}
+					return strings.Join(splitFlowers(greeting.Flowers), separator), nil
+				},
+			},
+			// Цветы по отдельности: один элемент на каждый эмодзи
+			"flowerList": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					greeting := p.Source.(GreetingResponse)
+					return splitFlowers(greeting.Flowers), nil
+				},
+			},
+			// Количество эмодзи-цветов с учётом многокодовых эмодзи
+			"flowersCount": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					greeting := p.Source.(GreetingResponse)
+					return len(splitFlowers(greeting.Flowers)), nil
+				},
+			},
+			// Названия цветов для экранных дикторов, по одному на каждый эмодзи
+			"flowerNames": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					greeting := p.Source.(GreetingResponse)
+					return flowerNames(greeting.Flowers), nil
+				},
+			},
+			// Текст с выделенными совпадениями для результатов поиска
+			"highlight": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Args: graphql.FieldConfigArgument{
+					"query": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					"open": &graphql.ArgumentConfig{
+						Type:         graphql.String,
+						DefaultValue: "<mark>",
+					},
+					"close": &graphql.ArgumentConfig{
+						Type:         graphql.String,
+						DefaultValue: "</mark>",
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					greeting := p.Source.(GreetingResponse)
+					return highlight(greeting.Text, p.Args["query"].(string), p.Args["open"].(string), p.Args["close"].(string)), nil
+				},
+			},
+			// Страница текста для телесуфлёров и длинных поздравлений (page с 1)
+			"textPage": &graphql.Field{
+				Type: graphql.NewNonNull(textPageType),
+				Args: graphql.FieldConfigArgument{
+					"page": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.Int),
+					},
+					"size": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.Int),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					greeting := p.Source.(GreetingResponse)
+					textPage, err := textPageOf(greeting.Text, p.Args["page"].(int), p.Args["size"].(int))
+					if err != nil {
+						return nil, err
+					}
+					return textPage, nil
+				},
+			},
+			// Почему выбрано это поздравление; null, если оно запрошено по birth_day
+			"selectionReason": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if reason := p.Source.(GreetingResponse).SelectionReason; reason != "" {
+						return reason, nil
+					}
+					return nil, nil
+				},
+			},
+			// Палитра для согласованной отрисовки открыток
+			"theme": &graphql.Field{
+				Type: graphql.NewNonNull(themeType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return themeFor(p.Source.(GreetingResponse)), nil
+				},
+			},
+		},
+	})
+
+	// 2. Поле greeting в корневом запросе
+	greetingField := &graphql.Field{
+		Type: greetingType,
+		Args: graphql.FieldConfigArgument{
+			"birth_day": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			// Подставить DEFAULT_FLOWERS, если у поздравления нет цветов
+			"useDefaultFlowers": &graphql.ArgumentConfig{
+				Type:         graphql.Boolean,
+				DefaultValue: false,
+			},
+			// Тон обращения; без аргумента возвращается исходный текст
+			"tone": &graphql.ArgumentConfig{
+				Type: toneEnum,
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			greeting, err := greetingFromArgs(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			if p.Args["useDefaultFlowers"].(bool) && greeting.Flowers == "" {
+				greeting.Flowers = defaultFlowers
+			}
+			if tone, ok := p.Args["tone"].(string); ok {
+				greeting.Text = applyTone(greeting.Text, tone)
+			}
+			logGreetingResolved(p.Context, greeting.BirthDay, "birth_day")
+			return greeting, nil
+		},
+	}
+
+	// Помещается ли текст поздравления в заданное число символов (рун) на открытке
+	fitsInWidthField := &graphql.Field{
+		Type: graphql.NewNonNull(graphql.Boolean),
+		Args: graphql.FieldConfigArgument{
+			"birth_day": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"maxChars": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			greeting, err := greetingFromArgs(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			maxChars := p.Args["maxChars"].(int)
+			if maxChars < 0 {
+				return nil, newCodedError(codeValidationFailed, "maxChars не может быть отрицательным")
+			}
+			return utf8.RuneCountInString(greeting.Text) <= maxChars, nil
+		},
+	}
+
+	// Несколько случайных поздравлений, например для перемешанной галереи
+	randomGreetingsField := &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(greetingType))),
+		Args: graphql.FieldConfigArgument{
+			"count": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"unique": &graphql.ArgumentConfig{
+				Type:         graphql.Boolean,
+				DefaultValue: false,
+			},
+			"seed": &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var rng *rand.Rand
+			reason := "random"
+			if seed, ok := p.Args["seed"].(int); ok {
+				rng = rand.New(rand.NewSource(int64(seed)))
+				reason = fmt.Sprintf("random seed %d", seed)
+			}
+			result, err := randomGreetings(rng, p.Args["count"].(int), p.Args["unique"].(bool))
+			if err != nil {
+				return nil, err
+			}
+			for i := range result {
+				result[i].SelectionReason = reason
+				logGreetingResolved(p.Context, result[i].BirthDay, reason)
+			}
+			return result, nil
+		},
+	}
+
+	// Одно поздравление, выбранное активной стратегией (SELECTION_STRATEGY)
+	randomGreetingField := &graphql.Field{
+		Type: graphql.NewNonNull(greetingType),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			greeting, err := selectGreeting(allGreetings())
+			if err != nil {
+				return nil, err
+			}
+			logGreetingResolved(p.Context, greeting.BirthDay, greeting.SelectionReason)
+			return greeting, nil
+		},
+	}
+
+	// Поздравление дня, недели или месяца: стабильно внутри периода
+	greetingOfThePeriodField := &graphql.Field{
+		Type: graphql.NewNonNull(greetingType),
+		Args: graphql.FieldConfigArgument{
+			"period": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(periodEnum),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			pool := allGreetings()
+			if err := nonEmptyPool(pool); err != nil {
+				return nil, err
+			}
+			period, now := p.Args["period"].(string), clock.Now()
+			greeting := selectForPeriod(pool, period, now)
+			greeting.SelectionReason = fmt.Sprintf("period %s %s", period, periodKey(period, now))
+			logGreetingResolved(p.Context, greeting.BirthDay, greeting.SelectionReason)
+			return greeting, nil
+		},
+	}
+
+	// Поздравления, среди цветов которых есть заданный эмодзи
+	greetingsContainingField := &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(greetingType))),
+		Args: graphql.FieldConfigArgument{
+			"emoji": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			emoji := p.Args["emoji"].(string)
+			if clusters := splitFlowers(emoji); len(clusters) != 1 || !isEmoji(clusters[0]) {
+				return nil, newCodedError(codeValidationFailed, "emoji должен содержать ровно один эмодзи")
+			}
+			result := greetingsContaining(emoji)
+			for _, greeting := range result {
+				logGreetingResolved(p.Context, greeting.BirthDay, "contains "+emoji)
+			}
+			return result, nil
+		},
+	}
+
+	schemaVersionField := &graphql.Field{
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return schemaVersion, nil
+		},
+	}
+
+	rootQuery := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"greeting":            greetingField,
+			"fitsInWidth":         fitsInWidthField,
+			"greetingOfThePeriod": greetingOfThePeriodField,
+			"greetingsContaining": greetingsContainingField,
+			"randomGreeting":      randomGreetingField,
+			"randomGreetings":     randomGreetingsField,
+			"schemaVersion":       schemaVersionField,
+		},
+	})
+
+	// Ремикс не входит в каталог, поэтому у него отдельный тип без birth_day
+	remixType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "RemixedGreeting",
+		Fields: graphql.Fields{
+			"text": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"flowers": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"flowerList": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return splitFlowers(p.Source.(RemixResponse).Flowers), nil
+				},
+			},
+			// birth_day поздравлений, из которых собран ремикс
+			"sources": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.Int))),
+			},
+		},
+	})
+
+	// Ремикс двух поздравлений; save: true отклоняется, пока каталог неизменяем
+	remixGreetingField := &graphql.Field{
+		Type: graphql.NewNonNull(remixType),
+		Args: graphql.FieldConfigArgument{
+			"birth_day1": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"birth_day2": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"save": &graphql.ArgumentConfig{
+				Type:         graphql.Boolean,
+				DefaultValue: false,
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			if save, _ := p.Args["save"].(bool); save {
+				return nil, newCodedError(codeValidationFailed, "сохранение ремиксов не поддерживается: каталог поздравлений неизменяем")
+			}
+			first, err := greetingFromArgs(map[string]interface{}{"birth_day": p.Args["birth_day1"]})
+			if err != nil {
+				return nil, err
+			}
+			second, err := greetingFromArgs(map[string]interface{}{"birth_day": p.Args["birth_day2"]})
+			if err != nil {
+				return nil, err
+			}
+			logGreetingResolved(p.Context, 0, fmt.Sprintf("remix of %d and %d", first.BirthDay, second.BirthDay))
+			return remixGreetings(first, second), nil
+		},
+	}
+
+	rootMutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"remixGreeting": remixGreetingField,
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: rootQuery, Mutation: rootMutation})
+}
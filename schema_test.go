@@ -0,0 +1,75 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIncludeAndSkipDirectives(t *testing.T) {
+	const query = `query($withText: Boolean!, $noFlowers: Boolean!) {
+		greeting(birth_day: 3) {
+			text @include(if: $withText)
+			flowers @skip(if: $noFlowers)
+		}
+	}`
+	tests := []struct {
+		withText, noFlowers bool
+		want                map[string]interface{}
+	}{
+		{true, false, map[string]interface{}{"text": greetings[2], "flowers": flowers[2]}},
+		{false, false, map[string]interface{}{"flowers": flowers[2]}},
+		{true, true, map[string]interface{}{"text": greetings[2]}},
+		{false, true, map[string]interface{}{}},
+	}
+	for _, tt := range tests {
+		result := execQuery(t, query, map[string]interface{}{"withText": tt.withText, "noFlowers": tt.noFlowers})
+		if len(result.Errors) > 0 {
+			t.Fatalf("withText=%v noFlowers=%v: %v", tt.withText, tt.noFlowers, result.Errors)
+		}
+		got := result.Data.(map[string]interface{})["greeting"]
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("withText=%v noFlowers=%v: %v, ожидалось %v", tt.withText, tt.noFlowers, got, tt.want)
+		}
+	}
+}
+
+func TestSelectionReason(t *testing.T) {
+	useFakeClock(t, date("2025-03-08 12:00"))
+	defer func(saved SelectionStrategy) { activeStrategy = saved }(activeStrategy)
+	activeStrategy, _ = newSelectionStrategy("daily")
+
+	tests := []struct {
+		query string
+		want  interface{}
+	}{
+		{`{ r: greeting(birth_day: 3) { selectionReason } }`, nil},
+		{`{ r: randomGreeting { selectionReason } }`, "strategy daily"},
+		{`{ r: greetingOfThePeriod(period: DAY) { selectionReason } }`, "period DAY 2025-03-08"},
+		{`{ r: greetingOfThePeriod(period: WEEK) { selectionReason } }`, "period WEEK 2025-W10"},
+		{`{ r: greetingOfThePeriod(period: MONTH) { selectionReason } }`, "period MONTH 2025-03"},
+		{`{ r: greetingsContaining(emoji: "💐") { selectionReason } }`, []interface{}{
+			map[string]interface{}{"selectionReason": nil},
+			map[string]interface{}{"selectionReason": nil},
+		}},
+		{`{ r: randomGreetings(count: 2, seed: 42) { selectionReason } }`, []interface{}{
+			map[string]interface{}{"selectionReason": "random seed 42"},
+			map[string]interface{}{"selectionReason": "random seed 42"},
+		}},
+		{`{ r: randomGreetings(count: 1) { selectionReason } }`, []interface{}{
+			map[string]interface{}{"selectionReason": "random"},
+		}},
+	}
+	for _, tt := range tests {
+		result := execQuery(t, tt.query, nil)
+		if len(result.Errors) > 0 {
+			t.Fatalf("%s: %v", tt.query, result.Errors)
+		}
+		got := result.Data.(map[string]interface{})["r"]
+		if m, ok := got.(map[string]interface{}); ok {
+			got = m["selectionReason"]
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %v, ожидалось %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+)
+
+// Стратегия выбора одного поздравления из пула (пул не пустой)
+type SelectionStrategy interface {
+	Name() string
+	Select(pool []GreetingResponse) GreetingResponse
+}
+
+// Активная стратегия, выбирается через SELECTION_STRATEGY
+var activeStrategy SelectionStrategy = uniformStrategy{}
+
+// Доступные стратегии по имени
+var selectionStrategies = map[string]func() SelectionStrategy{
+	"uniform":     func() SelectionStrategy { return uniformStrategy{} },
+	"daily":       func() SelectionStrategy { return dailyStrategy{} },
+	"round-robin": func() SelectionStrategy { return &roundRobinStrategy{} },
+}
+
+func newSelectionStrategy(name string) (SelectionStrategy, error) {
+	newStrategy, ok := selectionStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("неизвестная стратегия выбора %q", name)
+	}
+	return newStrategy(), nil
+}
+
+// Равновероятный случайный выбор
+type uniformStrategy struct{}
+
+func (uniformStrategy) Name() string { return "uniform" }
+
+func (uniformStrategy) Select(pool []GreetingResponse) GreetingResponse {
+	return pool[random.Intn(len(pool))]
+}
+
+// Одно и то же поздравление в течение календарного дня (UTC)
+type dailyStrategy struct{}
+
+func (dailyStrategy) Name() string { return "daily" }
+
+func (dailyStrategy) Select(pool []GreetingResponse) GreetingResponse {
+	return selectForPeriod(pool, periodDay, clock.Now())
+}
+
+// Периоды для детерминированного выбора
+const (
+	periodDay   = "DAY"
+	periodWeek  = "WEEK"
+	periodMonth = "MONTH"
+)
+
+// Ключ периода, в который попадает t (UTC): дата, ISO-неделя или месяц
+func periodKey(period string, t time.Time) string {
+	t = t.UTC()
+	switch period {
+	case periodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case periodMonth:
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// Одно и то же поздравление в пределах периода: хеш ключа периода,
+// посоленный названием периода, чтобы день, неделя и месяц не совпадали
+func selectForPeriod(pool []GreetingResponse, period string, t time.Time) GreetingResponse {
+	h := fnv.New32a()
+	h.Write([]byte(period + ":" + periodKey(period, t)))
+	return pool[int(h.Sum32()%uint32(len(pool)))]
+}
+
+// Поздравления по кругу в порядке пула: каждое показывается одинаково часто.
+// Курсор общий для всего сервера.
+type roundRobinStrategy struct {
+	cursor atomic.Uint64
+}
+
+func (*roundRobinStrategy) Name() string { return "round-robin" }
+
+func (s *roundRobinStrategy) Select(pool []GreetingResponse) GreetingResponse {
+	n := s.cursor.Add(1) - 1
+	return pool[n%uint64(len(pool))]
+}
+
+// Пустой пул (например, после фильтрации) даёт ошибку NO_MATCH; проверяется
+// перед любым выбором, чтобы не паниковать на Intn(0)
+func nonEmptyPool(pool []GreetingResponse) error {
+	if len(pool) == 0 {
+		return newCodedError(codeNoMatch, "нет поздравлений, подходящих под условия")
+	}
+	return nil
+}
+
+// Выбирает поздравление активной стратегией
+func selectGreeting(pool []GreetingResponse) (GreetingResponse, error) {
+	if err := nonEmptyPool(pool); err != nil {
+		return GreetingResponse{}, err
+	}
+	greeting := activeStrategy.Select(pool)
+	greeting.SelectionReason = strategyReason()
+	return greeting, nil
+}
+
+// Причина выбора стратегией для selectionReason и контентного лога
+func strategyReason() string {
+	return "strategy " + activeStrategy.Name()
+}
+
+// Весь каталог как пул для выбора
+func allGreetings() []GreetingResponse {
+	pool := make([]GreetingResponse, 0, len(greetings))
+	for i := range greetings {
+		if !isBlocked(i + 1) {
+			pool = append(pool, greetingAt(i+1))
+		}
+	}
+	return pool
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Блокирует все поздравления, чтобы любой пул оказался пустым
+func blockAllGreetings(t *testing.T) {
+	t.Helper()
+	saved := blockedIDs
+	blockedIDs = map[int]bool{}
+	for i := range greetings {
+		blockedIDs[i+1] = true
+	}
+	t.Cleanup(func() { blockedIDs = saved })
+}
+
+func TestSelectGreetingEmptyPool(t *testing.T) {
+	defer func(saved SelectionStrategy) { activeStrategy = saved }(activeStrategy)
+	for name := range selectionStrategies {
+		strategy, err := newSelectionStrategy(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		activeStrategy = strategy
+		for _, pool := range [][]GreetingResponse{nil, {}} {
+			if _, err := selectGreeting(pool); errorCode(err) != codeNoMatch {
+				t.Errorf("%s: ошибка %v, ожидался %s", name, err, codeNoMatch)
+			}
+		}
+	}
+}
+
+func TestRandomGreetingsEmptyPool(t *testing.T) {
+	blockAllGreetings(t)
+	for _, unique := range []bool{false, true} {
+		if _, err := randomGreetings(nil, 1, unique); errorCode(err) != codeNoMatch {
+			t.Errorf("unique=%v: ошибка %v, ожидался %s", unique, err, codeNoMatch)
+		}
+	}
+}
+
+func TestSelectionQueriesEmptyPool(t *testing.T) {
+	blockAllGreetings(t)
+	for _, query := range []string{
+		`{ randomGreeting { text } }`,
+		`{ randomGreetings(count: 3) { text } }`,
+		`{ greetingOfThePeriod(period: DAY) { text } }`,
+	} {
+		result := execQuery(t, query, nil)
+		if len(result.Errors) != 1 || result.Errors[0].Extensions["code"] != codeNoMatch {
+			t.Errorf("%s: ошибки %v, ожидался %s", query, result.Errors, codeNoMatch)
+		}
+	}
+	result := execQuery(t, `{ greetingsContaining(emoji: "🌷") { text } }`, nil)
+	if len(result.Errors) > 0 {
+		t.Fatalf("greetingsContaining: %v", result.Errors)
+	}
+	if list := result.Data.(map[string]interface{})["greetingsContaining"].([]interface{}); len(list) != 0 {
+		t.Errorf("greetingsContaining: %d поздравлений, ожидался пустой список", len(list))
+	}
+}
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestSelectForPeriodStableWithinPeriod(t *testing.T) {
+	pool := allGreetings()
+	tests := []struct {
+		period      string
+		first, last string
+	}{
+		{periodDay, "2025-03-08 00:00", "2025-03-08 23:59"},
+		// ISO-неделя 10 2025 года: с понедельника 3 по воскресенье 9 марта
+		{periodWeek, "2025-03-03 00:00", "2025-03-09 23:59"},
+		{periodMonth, "2025-03-01 00:00", "2025-03-31 23:59"},
+		// ISO-неделя 1 2026 года начинается 29 декабря 2025
+		{periodWeek, "2025-12-29 00:00", "2026-01-04 23:59"},
+	}
+	for _, tt := range tests {
+		want := selectForPeriod(pool, tt.period, date(tt.first))
+		for at := date(tt.first); !at.After(date(tt.last)); at = at.Add(7 * time.Hour) {
+			if got := selectForPeriod(pool, tt.period, at); got != want {
+				t.Errorf("%s: %s даёт %d, а начало периода — %d", tt.period, at, got.BirthDay, want.BirthDay)
+			}
+		}
+	}
+}
+
+func TestSelectForPeriodChangesAcrossPeriods(t *testing.T) {
+	pool := allGreetings()
+	tests := []struct {
+		period string
+		step   func(time.Time) time.Time
+	}{
+		{periodDay, func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }},
+		{periodWeek, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }},
+		{periodMonth, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+	}
+	for _, tt := range tests {
+		seen := map[int]bool{}
+		at := date("2025-01-01 12:00")
+		for i := 0; i < 12; i++ {
+			seen[selectForPeriod(pool, tt.period, at).BirthDay] = true
+			at = tt.step(at)
+		}
+		if len(seen) < 2 {
+			t.Errorf("%s: за 12 периодов выбрано одно и то же поздравление", tt.period)
+		}
+	}
+}
+
+func TestPeriodKey(t *testing.T) {
+	at := date("2025-03-08 15:00")
+	for period, want := range map[string]string{
+		periodDay:   "2025-03-08",
+		periodWeek:  "2025-W10",
+		periodMonth: "2025-03",
+	} {
+		if got := periodKey(period, at); got != want {
+			t.Errorf("periodKey(%s) = %q, ожидалось %q", period, got, want)
+		}
+	}
+}
+
+func TestUniformStrategyDeterministicWithSeed(t *testing.T) {
+	pool := allGreetings()
+	pick := func() []int {
+		var picks []int
+		for i := 0; i < 20; i++ {
+			picks = append(picks, uniformStrategy{}.Select(pool).BirthDay)
+		}
+		return picks
+	}
+
+	useSeededRandom(t, 42)
+	first := pick()
+	useSeededRandom(t, 42)
+	second := pick()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("один seed, разные выборы: %v и %v", first, second)
+	}
+
+	expected := rand.New(rand.NewSource(42))
+	for i, birth_day := range first {
+		if want := pool[expected.Intn(len(pool))].BirthDay; birth_day != want {
+			t.Fatalf("выбор %d: %d, ожидалось %d", i, birth_day, want)
+		}
+	}
+}
+
+func TestRoundRobinCyclesAndWraps(t *testing.T) {
+	pool := allGreetings()
+	strategy, err := newSelectionStrategy("round-robin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= len(pool); i++ {
+		want := pool[i%len(pool)]
+		if got := strategy.Select(pool); got != want {
+			t.Fatalf("вызов %d: birth_day %d, ожидался %d", i+1, got.BirthDay, want.BirthDay)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/handler"
+)
+
+// Разбирает значение PORT: "8080", ":8080" или полный адрес "0.0.0.0:8080".
+// Возвращает хост (может быть пустым) и числовой порт.
+func parsePort(value string) (host, port string, err error) {
+	port = value
+	if strings.Contains(value, ":") {
+		host, port, err = net.SplitHostPort(value)
+		if err != nil {
+			return "", "", fmt.Errorf("некорректный адрес в PORT %q: %v", value, err)
+		}
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return "", "", fmt.Errorf("некорректный порт в PORT %q: ожидается число от 1 до 65535", value)
+	}
+	return host, port, nil
+}
+
+// Адрес, по которому к серверу можно обратиться с этой же машины: localhost,
+// если сервер слушает все интерфейсы, иначе тот хост, что указан в PORT
+func localBaseURL(host, port string) string {
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// Читает булеву переменную окружения; пустое значение означает def
+func envBool(name string, def bool) (bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("некорректное значение %s=%q: ожидается true или false", name, value)
+	}
+	return b, nil
+}
+
+// Читает целочисленную переменную окружения; пустое значение означает def
+func envInt(name string, def int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("некорректное значение %s=%q: ожидается целое число", name, value)
+	}
+	return n, nil
+}
+
+// Читает длительность (например, 30s или 5m) из переменной окружения
+func envDuration(name string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("некорректное значение %s=%q: ожидается длительность, например 30s", name, value)
+	}
+	return d, nil
+}
+
+// Убирает завершающий слеш из пути, чтобы /graphql и /graphql/ вели к одному обработчику
+func stripTrailingSlash(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path := r.URL.Path; len(path) > 1 && strings.HasSuffix(path, "/") {
+			r.URL.Path = strings.TrimRight(path, "/")
+			if r.URL.Path == "" {
+				r.URL.Path = "/"
+			}
+			r.URL.RawPath = ""
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Настраивает GraphQL-сервер по переменным окружения и запускает его в фоне.
+// Возвращает сервер и локальный адрес GraphQL-эндпоинта для CLI.
+func startServer(quiet bool) (*http.Server, string) {
+	// LOG_FILE: дублировать лог в файл с ротацией по LOG_FILE_MAX_BYTES (по умолчанию 10 МБ)
+	if path := os.Getenv("LOG_FILE"); path != "" {
+		maxBytes, err := envInt("LOG_FILE_MAX_BYTES", 10<<20)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if maxBytes <= 0 {
+			log.Fatalf("LOG_FILE_MAX_BYTES должен быть положительным")
+		}
+		file, err := openRotatingFile(path, int64(maxBytes))
+		if err != nil {
+			log.Fatal(err)
+		}
+		logFile = newAsyncWriter(file, 1024)
+		log.SetOutput(io.MultiWriter(os.Stderr, logFile))
+	}
+
+	schema, err := newSchema()
+	if err != nil {
+		log.Fatalf("ошибка создания схемы GraphQL: %v", err)
+	}
+
+	if name := os.Getenv("FLOWER_FALLBACK_NAME"); name != "" {
+		flowerFallbackName = name
+	}
+	if value := os.Getenv("DEFAULT_FLOWERS"); value != "" {
+		defaultFlowers = value
+	}
+	if value := os.Getenv("BLOCKED_IDS"); value != "" {
+		blockedIDs, err = parseBlockedIDs(value)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	contentLogEnabled, err = envBool("CONTENT_LOG", false)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if name := os.Getenv("SELECTION_STRATEGY"); name != "" {
+		activeStrategy, err = newSelectionStrategy(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Создаём HTTP-обработчик; GRAPHIQL=false отключает любую браузерную песочницу,
+	// PLAYGROUND выбирает её вид: graphiql (по умолчанию) или apollo
+	graphiqlEnabled, err := envBool("GRAPHIQL", true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	playground := os.Getenv("PLAYGROUND")
+	if playground == "" {
+		playground = "graphiql"
+	}
+	if playground != "graphiql" && playground != "apollo" {
+		log.Fatalf("PLAYGROUND должен быть graphiql или apollo, получено %q", playground)
+	}
+
+	// Форматированный JSON удобен локально, но раздувает ответы в продакшене:
+	// по умолчанию он выключен при APP_ENV=production, PRETTY_JSON задаёт явно
+	prettyJSON, err := envBool("PRETTY_JSON", os.Getenv("APP_ENV") != "production")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var graphqlHandler http.Handler = handler.New(&handler.Config{
+		Schema:   &schema,
+		Pretty:   prettyJSON,
+		GraphiQL: graphiqlEnabled && playground == "graphiql",
+	})
+	errorStatus, err := envBool("HTTP_ERROR_STATUS", false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if errorStatus {
+		graphqlHandler = mapErrorStatus(graphqlHandler)
+	}
+	if graphiqlEnabled && playground == "apollo" {
+		graphqlHandler = apolloSandbox(graphqlHandler)
+	}
+
+	if value := os.Getenv("PUBLIC_BASE_URL"); value != "" {
+		publicBaseURL, err = parsePublicBaseURL(value)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Определяем порт из окружения или используем 8080 по умолчанию
+	portEnv := os.Getenv("PORT")
+	if portEnv == "" {
+		portEnv = "8080"
+	}
+	host, port, err := parsePort(portEnv)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Путь GraphQL-эндпоинта (по умолчанию /graphql), чтобы не занимать корень
+	graphqlPath := os.Getenv("GRAPHQL_PATH")
+	if graphqlPath == "" {
+		graphqlPath = "/graphql"
+	}
+	if !strings.HasPrefix(graphqlPath, "/") {
+		log.Fatalf("GRAPHQL_PATH должен начинаться с '/': %q", graphqlPath)
+	}
+	if graphqlPath != "/" {
+		graphqlPath = strings.TrimRight(graphqlPath, "/")
+	}
+
+	// Необязательный кэш ответов для повторяющихся запросов
+	cacheEnabled, err := envBool("RESPONSE_CACHE", false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cacheEnabled {
+		ttl, err := envDuration("RESPONSE_CACHE_TTL", 30*time.Second)
+		if err != nil {
+			log.Fatal(err)
+		}
+		size, err := envInt("RESPONSE_CACHE_SIZE", 1000)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if ttl <= 0 || size <= 0 {
+			log.Fatalf("RESPONSE_CACHE_TTL и RESPONSE_CACHE_SIZE должны быть положительными")
+		}
+		graphqlHandler = newResponseCache(ttl, size).middleware(graphqlHandler)
+		if !quiet {
+			log.Printf("Кэш ответов включён: TTL %s, до %d записей", ttl, size)
+		}
+	}
+
+	// Интроспекция меняется только вместе со schemaVersion, поэтому инструменты,
+	// часто запрашивающие схему, могут получать 304 по If-None-Match
+	graphqlHandler = conditionalIntrospection(graphqlHandler)
+
+	// Белый список операций из каталога OPERATIONS_DIR: по умолчанию запросы
+	// с произвольным текстом запрещены, OPERATIONS_STRICT=false их разрешает
+	if dir := os.Getenv("OPERATIONS_DIR"); dir != "" {
+		strict, err := envBool("OPERATIONS_STRICT", true)
+		if err != nil {
+			log.Fatal(err)
+		}
+		registry, err := newOperationRegistry(dir, strict)
+		if err != nil {
+			log.Fatal(err)
+		}
+		registry.reloadOnSIGHUP()
+		graphqlHandler = registry.middleware(graphqlHandler)
+		if !quiet {
+			log.Printf("Загружены операции из %s: %v", dir, registry.names())
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(graphqlPath, graphqlHandler)
+	mux.HandleFunc("/status", statusHandler)
+	mux.HandleFunc("/readyz", readyHandler)
+	mux.HandleFunc("GET /greeting/{birth_day}/page/{n}", textPageHandler)
+
+	// Публичный /surprise с собственным лимитом: SURPRISE_RATE_LIMIT запросов в минуту на клиента
+	surpriseLimit, err := envInt("SURPRISE_RATE_LIMIT", 10)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if surpriseLimit <= 0 {
+		log.Fatalf("SURPRISE_RATE_LIMIT должен быть положительным")
+	}
+	mux.Handle("/surprise", surpriseHandler(newWindowLimiter(surpriseLimit, time.Minute)))
+
+	// Что показывать браузеру в корне, если там нет GraphiQL: ROOT_PAGE=landing (по умолчанию),
+	// off или адрес для редиректа (например, /status)
+	var root http.Handler = mux
+	if !graphiqlEnabled || graphqlPath != "/" {
+		rootPage := os.Getenv("ROOT_PAGE")
+		if rootPage == "" {
+			rootPage = "landing"
+		}
+		root = rootLanding(mux, rootPage, graphqlPath)
+	}
+
+	// Общий лимит времени на запрос (например, REQUEST_TIMEOUT=30s); по умолчанию
+	// выключен, так как с ним каждый ответ буферизуется целиком
+	requestTimeout, err := envDuration("REQUEST_TIMEOUT", 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	root = stripTrailingSlash(root)
+	if requestTimeout > 0 {
+		root = timeoutRequests(root, requestTimeout)
+	}
+
+	preStopDelay, err = envDuration("PRESTOP_DELAY", 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Порт занимается до возврата, чтобы CLI и /readyz не обращались
+	// к серверу, который ещё не слушает
+	server := &http.Server{Addr: net.JoinHostPort(host, port), Handler: recoverPanics(root)}
+	ln, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		log.Fatalf("ошибка запуска сервера: %v", err)
+	}
+	if !quiet {
+		log.Printf("GraphQL сервер запущен на %s%s", localBaseURL(host, port), graphqlPath)
+	}
+	if graphiqlEnabled && !quiet {
+		log.Printf("Песочница %s доступна по адресу %s%s", playground, localBaseURL(host, port), graphqlPath)
+	}
+	go func() {
+		if err := server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("ошибка работы сервера: %v", err)
+		}
+	}()
+	ready.Store(true)
+
+	return server, localBaseURL(host, port) + graphqlPath
+}
+
+// Файл лога (LOG_FILE), закрывается при остановке сервера
+var logFile *asyncWriter
+
+// Пауза между снятием готовности и остановкой (PRESTOP_DELAY), чтобы балансировщик
+// успел перестать направлять запросы; по умолчанию 0
+var preStopDelay time.Duration
+
+// Корректно останавливает сервер: снимает готовность, ждёт preStopDelay
+// и дожидается завершения текущих запросов
+func shutdownServer(server *http.Server, quiet bool) {
+	ready.Store(false)
+	if preStopDelay > 0 {
+		log.Printf("Готовность снята, ждём %s перед остановкой", preStopDelay)
+		time.Sleep(preStopDelay)
+	}
+	if !quiet {
+		log.Printf("Останавливаем сервер...")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Fatalf("Ошибка при остановке сервера: %v", err)
+	}
+	if !quiet {
+		log.Printf("Сервер остановлен.")
+	}
+	if logFile != nil {
+		log.SetOutput(os.Stderr)
+		logFile.Close()
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParsePort(t *testing.T) {
+	tests := []struct {
+		value, host, port string
+	}{
+		{"8080", "", "8080"},
+		{":8080", "", "8080"},
+		{"127.0.0.1:9000", "127.0.0.1", "9000"},
+		{"[::1]:9000", "::1", "9000"},
+	}
+	for _, tt := range tests {
+		host, port, err := parsePort(tt.value)
+		if err != nil {
+			t.Errorf("parsePort(%q): %v", tt.value, err)
+			continue
+		}
+		if host != tt.host || port != tt.port {
+			t.Errorf("parsePort(%q) = %q, %q; ожидалось %q, %q", tt.value, host, port, tt.host, tt.port)
+		}
+	}
+}
+
+func TestLocalBaseURL(t *testing.T) {
+	tests := []struct {
+		port, want string
+	}{
+		{"8080", "http://localhost:8080"},
+		{":8080", "http://localhost:8080"},
+		{"0.0.0.0:8080", "http://localhost:8080"},
+		{"[::]:8080", "http://localhost:8080"},
+		{"127.0.0.2:18081", "http://127.0.0.2:18081"},
+		{"[::1]:18081", "http://[::1]:18081"},
+		{"example.local:9000", "http://example.local:9000"},
+	}
+	for _, tt := range tests {
+		host, port, err := parsePort(tt.port)
+		if err != nil {
+			t.Fatalf("parsePort(%q): %v", tt.port, err)
+		}
+		if got := localBaseURL(host, port); got != tt.want {
+			t.Errorf("PORT=%q: %q, ожидалось %q", tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestParsePortInvalid(t *testing.T) {
+	for _, value := range []string{"abc", ":abc", "::8080", "0", "65536", "-1", "host:", "8080:"} {
+		if _, _, err := parsePort(value); err == nil {
+			t.Errorf("parsePort(%q): ожидалась ошибка", value)
+		}
+	}
+}
+
+func TestStripTrailingSlash(t *testing.T) {
+	mux := http.NewServeMux()
+	for _, path := range []string{"/", "/graphql", "/status"} {
+		path := path
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != path {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(path))
+		})
+	}
+	h := stripTrailingSlash(mux)
+
+	tests := []struct{ request, handled string }{
+		{"/graphql", "/graphql"},
+		{"/graphql/", "/graphql"},
+		{"/graphql//", "/graphql"},
+		{"/status/", "/status"},
+		{"/", "/"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.request, nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != tt.handled {
+			t.Errorf("GET %s: %d %q, ожидался обработчик %s", tt.request, rec.Code, rec.Body.String(), tt.handled)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+)
+
+// Готовность принимать трафик; снимается в начале остановки сервера
+var ready atomic.Bool
+
+// Проба готовности для балансировщика: 200, пока сервер принимает трафик, иначе 503
+func readyHandler(w http.ResponseWriter, r *http.Request) {
+	if !ready.Load() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok"))
+}
+
+// Сведения о конфигурации сервиса для операторов
+type statusResponse struct {
+	SelectionStrategy string `json:"selectionStrategy"`
+	Greetings         int    `json:"greetings"`
+	Blocked           int    `json:"blocked"`
+	CacheHits         int64  `json:"cacheHits"`
+	CacheMisses       int64  `json:"cacheMisses"`
+}
+
+func statusHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(statusResponse{
+		SelectionStrategy: activeStrategy.Name(),
+		Greetings:         len(greetings),
+		Blocked:           len(blockedIDs),
+		CacheHits:         cacheHits.Load(),
+		CacheMisses:       cacheMisses.Load(),
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Ограничитель «не больше limit запросов за window» для каждого клиента
+type windowLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	start  time.Time
+	counts map[string]int
+}
+
+func newWindowLimiter(limit int, window time.Duration) *windowLimiter {
+	return &windowLimiter{limit: limit, window: window, start: clock.Now(), counts: make(map[string]int)}
+}
+
+// Разрешает запрос клиента key или возвращает время до начала следующего окна
+func (l *windowLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := clock.Now()
+	if now.Sub(l.start) >= l.window {
+		l.start = now
+		l.counts = make(map[string]int)
+	}
+	if l.counts[key] >= l.limit {
+		return false, l.start.Add(l.window).Sub(now)
+	}
+	l.counts[key]++
+	return true, 0
+}
+
+// GET /surprise: случайное поздравление для случайных посетителей,
+// с собственным, более строгим ограничением частоты
+func surpriseHandler(limiter *windowLimiter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "метод не поддерживается", http.StatusMethodNotAllowed)
+			return
+		}
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+		if ok, retryAfter := limiter.allow(client); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			http.Error(w, "слишком много запросов", http.StatusTooManyRequests)
+			return
+		}
+
+		greeting, err := selectGreeting(allGreetings())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		logGreetingResolved(r.Context(), greeting.BirthDay, greeting.SelectionReason)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(greeting)
+	}
+}
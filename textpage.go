@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Страница текста поздравления для постраничного показа
+type TextPage struct {
+	Text       string `json:"text"`
+	Page       int    `json:"page"`
+	TotalPages int    `json:"totalPages"`
+}
+
+var textPageType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "TextPage",
+	Fields: graphql.Fields{
+		"text": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"page": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"totalPages": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+	},
+})
+
+// Делит текст на страницы не длиннее size рун, разрывая по границам слов.
+// Слово длиннее size разрезается. Короткий текст даёт одну страницу.
+func paginateText(text string, size int) []string {
+	pages := []string{}
+	var current strings.Builder
+	currentLen := 0
+	flush := func() {
+		if currentLen > 0 {
+			pages = append(pages, current.String())
+			current.Reset()
+			currentLen = 0
+		}
+	}
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > size {
+			flush()
+			runes := []rune(word)
+			pages = append(pages, string(runes[:size]))
+			word = string(runes[size:])
+		}
+		wordLen := utf8.RuneCountInString(word)
+		if currentLen > 0 && currentLen+1+wordLen > size {
+			flush()
+		}
+		if currentLen > 0 {
+			current.WriteByte(' ')
+			currentLen++
+		}
+		current.WriteString(word)
+		currentLen += wordLen
+	}
+	flush()
+	if len(pages) == 0 {
+		pages = append(pages, "")
+	}
+	return pages
+}
+
+// Страница page (с 1) текста, разбитого по size рун; общая для поля textPage
+// и эндпоинта /greeting/{birth_day}/page/{n}
+func textPageOf(text string, page, size int) (TextPage, error) {
+	if size < 1 {
+		return TextPage{}, newCodedError(codeValidationFailed, "size должен быть положительным")
+	}
+	pages := paginateText(text, size)
+	if page < 1 || page > len(pages) {
+		return TextPage{}, newCodedError(codeValidationFailed, fmt.Sprintf("page должен быть от 1 до %d", len(pages)))
+	}
+	return TextPage{Text: pages[page-1], Page: page, TotalPages: len(pages)}, nil
+}
+
+// GET /greeting/{birth_day}/page/{n}?size=N: страница текста поздравления в JSON.
+// Ошибки — JSON {"error", "code"}: 404 для INVALID_ID и BLOCKED, 400 для остальных.
+func textPageHandler(w http.ResponseWriter, r *http.Request) {
+	birth_day, err := strconv.Atoi(r.PathValue("birth_day"))
+	if err != nil {
+		writeJSONError(w, newCodedError(codeInvalidID, "birth_day должен быть целым числом"))
+		return
+	}
+	greeting, err := greetingFromArgs(map[string]interface{}{"birth_day": birth_day})
+	if err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	page, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil {
+		writeJSONError(w, newCodedError(codeValidationFailed, "номер страницы должен быть целым числом"))
+		return
+	}
+	size, err := strconv.Atoi(r.URL.Query().Get("size"))
+	if err != nil {
+		writeJSONError(w, newCodedError(codeValidationFailed, "параметр size обязателен и должен быть целым числом"))
+		return
+	}
+	textPage, err := textPageOf(greeting.Text, page, size)
+	if err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	logGreetingResolved(r.Context(), greeting.BirthDay, "birth_day")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(textPage)
+}
+
+func writeJSONError(w http.ResponseWriter, err error) {
+	status, code := http.StatusBadRequest, ""
+	var coded *codedError
+	if errors.As(err, &coded) {
+		code = coded.code
+		if code == codeInvalidID || code == codeBlocked {
+			status = http.StatusNotFound
+		}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error(), "code": code})
+}
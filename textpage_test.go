@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPaginateText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		size int
+		want []string
+	}{
+		{"по границам слов", "С праздником весны и любви", 12, []string{"С праздником", "весны и", "любви"}},
+		{"слово длиннее страницы", "ab поздравляю cd", 5, []string{"ab", "поздр", "авляю", "cd"}},
+		{"пустой текст", "", 10, []string{""}},
+		{"только пробелы", "   ", 10, []string{""}},
+		{"короткий текст", "С 8 Марта!", 100, []string{"С 8 Марта!"}},
+	}
+	for _, tt := range tests {
+		if got := paginateText(tt.text, tt.size); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %q, ожидалось %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTextPageHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /greeting/{birth_day}/page/{n}", textPageHandler)
+
+	pages := paginateText(greetings[8], 30)
+	tests := []struct {
+		path   string
+		status int
+		code   string
+	}{
+		{"/greeting/9/page/2?size=30", http.StatusOK, ""},
+		{"/greeting/99/page/1?size=30", http.StatusNotFound, codeInvalidID},
+		{"/greeting/abc/page/1?size=30", http.StatusNotFound, codeInvalidID},
+		{"/greeting/9/page/99?size=30", http.StatusBadRequest, codeValidationFailed},
+		{"/greeting/9/page/1", http.StatusBadRequest, codeValidationFailed},
+		{"/greeting/9/page/1?size=0", http.StatusBadRequest, codeValidationFailed},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s: статус %d, ожидался %d", tt.path, rec.Code, tt.status)
+			continue
+		}
+		if tt.status == http.StatusOK {
+			var got TextPage
+			json.Unmarshal(rec.Body.Bytes(), &got)
+			want := TextPage{Text: pages[1], Page: 2, TotalPages: len(pages)}
+			if got != want {
+				t.Errorf("%s: %+v, ожидалось %+v", tt.path, got, want)
+			}
+			continue
+		}
+		var body map[string]string
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		if body["code"] != tt.code {
+			t.Errorf("%s: код %q, ожидался %q", tt.path, body["code"], tt.code)
+		}
+	}
+}
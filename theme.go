@@ -0,0 +1,38 @@
+package main
+
+import "github.com/graphql-go/graphql"
+
+// Цветовая палитра открытки (цвета в формате #RRGGBB)
+type Theme struct {
+	Background string `json:"background"`
+	Text       string `json:"text"`
+	Accent     string `json:"accent"`
+}
+
+// Палитра по умолчанию. Все поздравления сейчас относятся к 8 Марта,
+// поэтому отдельной палитры на каждый праздник пока нет.
+var defaultTheme = Theme{
+	Background: "#FFF0F5",
+	Text:       "#4A1A2C",
+	Accent:     "#E75480",
+}
+
+// Палитра для поздравления
+func themeFor(GreetingResponse) Theme {
+	return defaultTheme
+}
+
+var themeType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Theme",
+	Fields: graphql.Fields{
+		"background": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"text": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"accent": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+	},
+})
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Ограничивает время обработки запроса (REQUEST_TIMEOUT). Ответ буферизуется;
+// если обработчик не уложился, клиент получает 503 с JSON-телом, а контекст
+// запроса отменяется. Потоковых эндпоинтов у сервиса нет, поэтому лимит
+// применяется ко всем маршрутам; такие эндпоинты нужно будет исключить.
+func timeoutRequests(next http.Handler, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		buf := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		done := make(chan struct{})
+		var panicked interface{}
+		go func() {
+			defer close(done)
+			defer func() { panicked = recover() }()
+			next.ServeHTTP(buf, r.WithContext(ctx))
+		}()
+
+		select {
+		case <-done:
+			if panicked != nil {
+				// Паника передаётся в recoverPanics в горутине запроса
+				panic(panicked)
+			}
+			for k, v := range buf.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(buf.status)
+			w.Write(buf.body.Bytes())
+		case <-ctx.Done():
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]string{"error": "превышено время обработки запроса"})
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeoutRequestsSlowHandler(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+			w.Write([]byte("поздно"))
+		}
+	})
+	rec := httptest.NewRecorder()
+	timeoutRequests(slow, 20*time.Millisecond).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("статус %d, ожидался 503", rec.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == "" {
+		t.Errorf("тело не JSON с полем error: %q", rec.Body.String())
+	}
+}
+
+func TestTimeoutRequestsFastHandler(t *testing.T) {
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "1")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("готово"))
+	})
+	rec := httptest.NewRecorder()
+	timeoutRequests(fast, time.Second).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusCreated || rec.Body.String() != "готово" || rec.Header().Get("X-Test") != "1" {
+		t.Errorf("ответ обработчика искажён: %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Правила смены тона. Тексты каталога написаны на «вы», поэтому FORMAL
+// возвращает их без изменений. CASUAL переводит обращение на «ты» заменой
+// целых слов по словарю (регистр первой буквы сохраняется). PLAYFUL делает
+// то же и добавляет в конец эмодзи. Чтобы поддержать новые обороты,
+// достаточно дополнить словарь.
+type toneRule struct {
+	replacements map[string]string // слово в нижнем регистре -> замена
+	suffix       string
+}
+
+var casualReplacements = map[string]string{
+	"вы":          "ты",
+	"вас":         "тебя",
+	"вам":         "тебе",
+	"вами":        "тобой",
+	"ваш":         "твой",
+	"ваша":        "твоя",
+	"ваше":        "твоё",
+	"ваши":        "твои",
+	"вашей":       "твоей",
+	"вашего":      "твоего",
+	"вашему":      "твоему",
+	"ваших":       "твоих",
+	"будьте":      "будь",
+	"оставайтесь": "оставайся",
+	"сияйте":      "сияй",
+	// Краткие прилагательные после «будь» — в женском роде, как и обращения в каталоге
+	"счастливы":   "счастлива",
+	"любимы":      "любима",
+	"неповторимы": "неповторима",
+}
+
+var toneRules = map[string]toneRule{
+	"FORMAL":  {},
+	"CASUAL":  {replacements: casualReplacements},
+	"PLAYFUL": {replacements: casualReplacements, suffix: " 🎉"},
+}
+
+var toneEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "Tone",
+	Values: graphql.EnumValueConfigMap{
+		"FORMAL":  &graphql.EnumValueConfig{Value: "FORMAL"},
+		"CASUAL":  &graphql.EnumValueConfig{Value: "CASUAL"},
+		"PLAYFUL": &graphql.EnumValueConfig{Value: "PLAYFUL"},
+	},
+})
+
+var wordPattern = regexp.MustCompile(`\p{L}+`)
+
+// Применяет к тексту правила тона
+func applyTone(text, tone string) string {
+	rule := toneRules[tone]
+	if len(rule.replacements) > 0 {
+		text = wordPattern.ReplaceAllStringFunc(text, func(word string) string {
+			replacement, ok := rule.replacements[strings.ToLower(word)]
+			if !ok {
+				return word
+			}
+			if first, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(first) {
+				r, size := utf8.DecodeRuneInString(replacement)
+				return string(unicode.ToUpper(r)) + replacement[size:]
+			}
+			return replacement
+		})
+	}
+	return text + rule.suffix
+}